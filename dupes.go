@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/rinimisini112/gls/dupes"
+	"github.com/rinimisini112/gls/filter"
+	"github.com/rinimisini112/gls/output"
+)
+
+func showDupesHelp() {
+	fmt.Println("\n🧬 Usage: gls dupes [options] [directories]")
+	fmt.Println("Options:")
+	fmt.Println("  --format=table|json|ndjson  Output format (default table)")
+	fmt.Println("  --min-size=SIZE  Ignore files smaller than SIZE (e.g. 1M)")
+	fmt.Println("  -a           Include hidden files")
+	fmt.Println("  --no-ignore  Do not honor .gitignore, .ignore and .glsignore")
+	fmt.Println("  --include=GLOB, --exclude=GLOB  Limit which paths are compared")
+	fmt.Println("  --timeout=DURATION  Stop scanning after DURATION")
+	fmt.Println("  --hardlink   Replace duplicates with hardlinks to the first copy (asks first, table output only)")
+	fmt.Println("  --delete     Delete all but the first copy of each set (asks first, table output only)")
+}
+
+func confirm(msg string) bool {
+	fmt.Print(msg)
+	var input string
+	fmt.Scanln(&input)
+	return strings.ToLower(input) == "y"
+}
+
+func runDupes(args []string) {
+	var dirs []string
+	format := output.FormatTable
+	pathFilter := &filter.Set{}
+	opts := dupes.Options{}
+	hardlink := false
+	remove := false
+	var timeout time.Duration
+
+	for _, arg := range args {
+		var err error
+		switch {
+		case arg == "--help" || arg == "-h":
+			showDupesHelp()
+			return
+		case strings.HasPrefix(arg, "--format="):
+			format, err = output.ParseFormat(strings.TrimPrefix(arg, "--format="))
+			if err == nil && format.IsDelimited() {
+				err = fmt.Errorf("dupes supports table, json and ndjson output")
+			}
+		case strings.HasPrefix(arg, "--min-size="):
+			opts.MinSize, err = filter.ParseSize(strings.TrimPrefix(arg, "--min-size="))
+		case arg == "-a":
+			opts.ShowHidden = true
+		case arg == "--no-ignore":
+			opts.Finder.NoIgnore = true
+		case strings.HasPrefix(arg, "--include="):
+			err = pathFilter.AddInclude(strings.TrimPrefix(arg, "--include="))
+		case strings.HasPrefix(arg, "--exclude="):
+			err = pathFilter.AddExclude(strings.TrimPrefix(arg, "--exclude="))
+		case strings.HasPrefix(arg, "--timeout="):
+			timeout, err = time.ParseDuration(strings.TrimPrefix(arg, "--timeout="))
+		case arg == "--hardlink":
+			hardlink = true
+		case arg == "--delete":
+			remove = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Println("❌ Invalid option:", arg)
+			showDupesHelp()
+			return
+		default:
+			dirs = append(dirs, arg)
+		}
+		if err != nil {
+			fmt.Println("❌ Error:", err)
+			return
+		}
+	}
+
+	if hardlink && remove {
+		fmt.Println("❌ Error: choose either --hardlink or --delete")
+		return
+	}
+	if (hardlink || remove) && format.IsMachine() {
+		fmt.Println("❌ Error: --hardlink and --delete ask for confirmation and need the table output")
+		return
+	}
+	if len(dirs) == 0 {
+		dirs = append(dirs, ".")
+	}
+	opts.Finder.Filter = pathFilter
+
+	ctx, cancel := searchContext(timeout)
+	sets, err := dupes.Find(ctx, dirs, opts)
+	complete := err == nil && ctx.Err() == nil
+	cancel()
+	reportSearchError(err)
+
+	if format.IsMachine() {
+		if err := output.WriteDuplicatesJSON(os.Stdout, sets, format); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		return
+	}
+	output.WriteDuplicates(os.Stdout, sets)
+
+	if len(sets) == 0 || (!hardlink && !remove) {
+		return
+	}
+	if !complete {
+		fmt.Println("❌ Error: the scan did not finish cleanly, so nothing was changed")
+		return
+	}
+
+	var files int
+	var wasted int64
+	for _, set := range sets {
+		files += len(set.Files) - 1
+		wasted += set.Wasted()
+	}
+
+	action, apply := "Replace %d duplicates with hardlinks, saving %s? (y/n): ", dupes.Hardlink
+	if remove {
+		action, apply = "Delete %d duplicates, freeing %s? (y/n): ", dupes.Delete
+	}
+	if !confirm(fmt.Sprintf(action, files, humanize.Bytes(uint64(wasted)))) {
+		fmt.Println("Nothing changed")
+		return
+	}
+
+	for _, set := range sets {
+		if err := apply(set); err != nil {
+			fmt.Fprintln(os.Stderr, "⚠️", err)
+		}
+	}
+	fmt.Println("✅ Done")
+}
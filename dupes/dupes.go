@@ -0,0 +1,265 @@
+package dupes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rinimisini112/gls/finder"
+	"github.com/rinimisini112/gls/structures"
+	"github.com/rinimisini112/gls/sysinfo"
+)
+
+const partialBlock = 4096
+
+type Options struct {
+	Finder     finder.Options
+	ShowHidden bool
+	MinSize    int64
+}
+
+type candidate struct {
+	file structures.FileInfo
+	hash string
+}
+
+// Find groups identical regular files below dirs. Candidates are narrowed by
+// size, then by a hash of their first and last blocks, and only the survivors
+// are hashed in full. Files that are already hardlinks of each other count
+// once.
+func Find(ctx context.Context, dirs []string, opts Options) ([]structures.DuplicateSet, error) {
+	opts.Finder.FilterType = "file"
+	opts.Finder.NoHidden = !opts.ShowHidden
+
+	bySize := make(map[int64][]candidate)
+	seen := make(map[finder.FileID]bool)
+	var errs []error
+
+	for _, dir := range dirs {
+		err := finder.StreamContext(ctx, "", dir, opts.Finder, func(file structures.FileInfo) {
+			if file.IsLink || !file.Mode.IsRegular() || file.RawSize == 0 || file.RawSize < opts.MinSize {
+				return
+			}
+
+			id := finder.FileID{Dev: file.Dev, Ino: file.Inode}
+			if seen[id] {
+				return
+			}
+			seen[id] = true
+			bySize[file.RawSize] = append(bySize[file.RawSize], candidate{file: file})
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	var groups [][]candidate
+	for _, group := range bySize {
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+
+	groups, err := refine(ctx, groups, partialHash)
+	errs = append(errs, err)
+	groups, err = refine(ctx, groups, fullHash)
+	errs = append(errs, err)
+
+	sets := make([]structures.DuplicateSet, 0, len(groups))
+	for _, group := range groups {
+		set := structures.DuplicateSet{Size: group[0].file.RawSize, Hash: group[0].hash}
+		for _, c := range group {
+			set.Files = append(set.Files, c.file)
+		}
+		sort.Slice(set.Files, func(i, j int) bool { return set.Files[i].Path < set.Files[j].Path })
+		sets = append(sets, set)
+	}
+
+	sort.Slice(sets, func(i, j int) bool {
+		if sets[i].Wasted() != sets[j].Wasted() {
+			return sets[i].Wasted() > sets[j].Wasted()
+		}
+		return sets[i].Files[0].Path < sets[j].Files[0].Path
+	})
+
+	return sets, errors.Join(errs...)
+}
+
+// refine hashes every candidate with hash and splits each group by the
+// result, dropping files left without a twin. A candidate this pass could not
+// hash, because it failed to read or ctx ended first, is dropped as well
+// rather than grouped by an earlier, weaker hash.
+func refine(ctx context.Context, groups [][]candidate, hash func(path string, size int64) (string, error)) ([][]candidate, error) {
+	var all []*candidate
+	for _, group := range groups {
+		for i := range group {
+			all = append(all, &group[i])
+		}
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(all) {
+		workers = len(all)
+	}
+
+	sums := make([]string, len(all))
+	failed := make([]error, len(all))
+	var next atomic.Int64
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(all) {
+					return
+				}
+
+				c := all[i]
+				sum, err := hash(c.file.Path, c.file.RawSize)
+				if err != nil {
+					failed[i] = &finder.DirError{Path: c.file.Path, Err: err}
+					continue
+				}
+				sums[i] = sum
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range all {
+		c.hash = sums[i]
+	}
+
+	var refined [][]candidate
+	for _, group := range groups {
+		byHash := make(map[string][]candidate)
+		for _, c := range group {
+			if c.hash != "" {
+				byHash[c.hash] = append(byHash[c.hash], c)
+			}
+		}
+		for _, split := range byHash {
+			if len(split) > 1 {
+				refined = append(refined, split)
+			}
+		}
+	}
+
+	return refined, errors.Join(append(failed, ctx.Err())...)
+}
+
+func partialHash(path string, size int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.CopyN(h, f, min(size, partialBlock)); err != nil {
+		return "", err
+	}
+	if size > 2*partialBlock {
+		if _, err := f.Seek(-partialBlock, io.SeekEnd); err != nil {
+			return "", err
+		}
+		if _, err := io.CopyN(h, f, partialBlock); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func fullHash(path string, size int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// unchanged checks that file is still the regular file that was hashed, by
+// its inode, size and mtime.
+func unchanged(file structures.FileInfo) error {
+	info, err := os.Lstat(file.Path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() || sysinfo.InodeOf(info).Ino != file.Inode ||
+		info.Size() != file.RawSize || !info.ModTime().Equal(file.ModTime) {
+		return fmt.Errorf("%s: changed since it was scanned", file.Path)
+	}
+	return nil
+}
+
+// Hardlink replaces every file in the set but the first with a hardlink to
+// it. Each replacement goes through a temporary link and a rename, so a file
+// is never missing if something fails halfway. Files that changed since the
+// scan are left alone, and so is the whole set if the first one did.
+func Hardlink(set structures.DuplicateSet) error {
+	keep := set.Files[0]
+	if err := unchanged(keep); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, file := range set.Files[1:] {
+		if err := unchanged(file); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if file.Dev != keep.Dev {
+			errs = append(errs, fmt.Errorf("%s: on a different filesystem from %s", file.Path, keep.Path))
+			continue
+		}
+
+		tmp := filepath.Join(filepath.Dir(file.Path), fmt.Sprintf(".%s.gls-link", file.Name))
+		if err := os.Link(keep.Path, tmp); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.Rename(tmp, file.Path); err != nil {
+			os.Remove(tmp)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Delete removes every file in the set but the first, with the same checks
+// as Hardlink.
+func Delete(set structures.DuplicateSet) error {
+	if err := unchanged(set.Files[0]); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, file := range set.Files[1:] {
+		if err := unchanged(file); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.Remove(file.Path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
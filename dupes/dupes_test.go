@@ -0,0 +1,267 @@
+package dupes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+func writeFile(t *testing.T, path string, data []byte) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// blocks builds a file that starts and ends with the same partial blocks as
+// every other call, differing only in its middle byte.
+func blocks(middle byte) []byte {
+	data := bytes.Repeat([]byte{'x'}, 3*partialBlock)
+	data[len(data)/2] = middle
+	return data
+}
+
+func candidates(t *testing.T, paths ...string) []candidate {
+	t.Helper()
+	var group []candidate
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		group = append(group, candidate{file: structures.FileInfo{Path: path, RawSize: info.Size()}})
+	}
+	return group
+}
+
+func paths(group []candidate) []string {
+	var out []string
+	for _, c := range group {
+		out = append(out, filepath.Base(c.file.Path))
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestRefine(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, filepath.Join(dir, "a"), blocks('1'))
+	b := writeFile(t, filepath.Join(dir, "b"), blocks('1'))
+	c := writeFile(t, filepath.Join(dir, "c"), blocks('2'))
+
+	groups := [][]candidate{candidates(t, a, b, c)}
+	groups, err := refine(context.Background(), groups, partialHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || len(groups[0]) != 3 {
+		t.Fatalf("partial pass = %d groups, want all three files together", len(groups))
+	}
+
+	groups, err = refine(context.Background(), groups, fullHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || !slices.Equal(paths(groups[0]), []string{"a", "b"}) {
+		t.Fatalf("full pass = %v, want [a b]", groups)
+	}
+}
+
+func TestRefineDropsUnhashed(t *testing.T) {
+	group := []candidate{
+		{file: structures.FileInfo{Path: "a"}, hash: "partial"},
+		{file: structures.FileInfo{Path: "b"}, hash: "partial"},
+		{file: structures.FileInfo{Path: "c"}, hash: "partial"},
+	}
+	failing := func(path string, size int64) (string, error) {
+		if path == "c" {
+			return "", errors.New("read failed")
+		}
+		return "full", nil
+	}
+
+	groups, err := refine(context.Background(), [][]candidate{slices.Clone(group)}, failing)
+	if err == nil {
+		t.Error("refine hid the read error")
+	}
+	if len(groups) != 1 || !slices.Equal(paths(groups[0]), []string{"a", "b"}) {
+		t.Errorf("after a failed read = %v, want [a b]", groups)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	groups, err = refine(ctx, [][]candidate{slices.Clone(group)}, failing)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("refine with a cancelled context returned %v", err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("refine with a cancelled context kept %v", groups)
+	}
+}
+
+func find(t *testing.T, ctx context.Context, dir string, opts Options) ([]structures.DuplicateSet, error) {
+	t.Helper()
+	opts.Finder.NoIgnore = true
+	return Find(ctx, []string{dir}, opts)
+}
+
+func names(set structures.DuplicateSet) []string {
+	var out []string
+	for _, file := range set.Files {
+		out = append(out, file.Name)
+	}
+	return out
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a"), blocks('1'))
+	writeFile(t, filepath.Join(dir, "sub", "b"), blocks('1'))
+	writeFile(t, filepath.Join(dir, "c"), blocks('2'))
+	writeFile(t, filepath.Join(dir, ".h", "d"), blocks('1'))
+	writeFile(t, filepath.Join(dir, "small1"), []byte("same"))
+	writeFile(t, filepath.Join(dir, "small2"), []byte("same"))
+	writeFile(t, filepath.Join(dir, "empty1"), nil)
+	writeFile(t, filepath.Join(dir, "empty2"), nil)
+	if err := os.Link(filepath.Join(dir, "a"), filepath.Join(dir, "a-link")); err != nil {
+		t.Fatal(err)
+	}
+
+	sets, err := find(t, context.Background(), dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 2 {
+		t.Fatalf("found %d sets, want 2", len(sets))
+	}
+	if got := names(sets[0]); len(got) != 2 || got[1] != "b" {
+		t.Errorf("largest set = %v, want a or a-link with b", got)
+	}
+	if got := names(sets[1]); !slices.Equal(got, []string{"small1", "small2"}) {
+		t.Errorf("second set = %v, want [small1 small2]", got)
+	}
+
+	sets, err = find(t, context.Background(), dir, Options{ShowHidden: true, MinSize: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 1 || len(sets[0].Files) != 3 {
+		t.Errorf("with -a and a minimum size = %v, want a, .h/d and b together", sets)
+	}
+}
+
+func TestFindCancelled(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a"), blocks('1'))
+	writeFile(t, filepath.Join(dir, "b"), blocks('1'))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sets, err := find(t, ctx, dir, Options{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Find with a cancelled context returned %v", err)
+	}
+	if len(sets) != 0 {
+		t.Errorf("Find with a cancelled context reported %d sets", len(sets))
+	}
+}
+
+func findOne(t *testing.T, dir string) structures.DuplicateSet {
+	t.Helper()
+	sets, err := find(t, context.Background(), dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 1 {
+		t.Fatalf("found %d sets, want 1", len(sets))
+	}
+	return sets[0]
+}
+
+func sameFile(t *testing.T, a, b string) bool {
+	t.Helper()
+	ai, err := os.Stat(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return os.SameFile(ai, bi)
+}
+
+func TestHardlink(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, filepath.Join(dir, "a"), blocks('1'))
+	b := writeFile(t, filepath.Join(dir, "b"), blocks('1'))
+
+	if err := Hardlink(findOne(t, dir)); err != nil {
+		t.Fatal(err)
+	}
+	if !sameFile(t, a, b) {
+		t.Error("b was not replaced with a hardlink to a")
+	}
+	if data, err := os.ReadFile(b); err != nil || !bytes.Equal(data, blocks('1')) {
+		t.Errorf("b lost its content: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("left %d entries behind, want 2", len(entries))
+	}
+}
+
+// touch rewrites path with data of the same size and moves its mtime, as an
+// edit between the scan and the action would.
+func touch(t *testing.T, path string, data []byte) {
+	t.Helper()
+	writeFile(t, path, data)
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHardlinkChanged(t *testing.T) {
+	for _, changed := range []string{"a", "b"} {
+		dir := t.TempDir()
+		a := writeFile(t, filepath.Join(dir, "a"), blocks('1'))
+		b := writeFile(t, filepath.Join(dir, "b"), blocks('1'))
+		set := findOne(t, dir)
+
+		touch(t, filepath.Join(dir, changed), blocks('2'))
+		if err := Hardlink(set); err == nil {
+			t.Errorf("Hardlink went ahead after %s changed", changed)
+		}
+		if sameFile(t, a, b) {
+			t.Errorf("b was linked to a after %s changed", changed)
+		}
+	}
+}
+
+func TestDeleteChanged(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, filepath.Join(dir, "a"), blocks('1'))
+	b := writeFile(t, filepath.Join(dir, "b"), blocks('1'))
+	set := findOne(t, dir)
+
+	if err := os.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := Delete(set); err == nil {
+		t.Error("Delete went ahead without the copy it keeps")
+	}
+	if _, err := os.Stat(b); err != nil {
+		t.Errorf("the last copy was deleted: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+type Criteria struct {
+	TimeField string
+
+	specs      []string
+	preds      []Predicate
+	needsOwner bool
+	needsBirth bool
+	usesTime   bool
+}
+
+func (c *Criteria) Add(name, spec string) error {
+	var pred Predicate
+	var err error
+
+	switch name {
+	case "size":
+		pred, err = SizePredicate(spec)
+	case "newer", "older":
+		var ref time.Time
+		ref, err = ParseTime(spec, time.Now())
+		newer := name == "newer"
+		c.usesTime = true
+		pred = func(file structures.FileInfo) bool {
+			t := file.Time(c.TimeField)
+			if newer {
+				return t.After(ref)
+			}
+			return t.Before(ref)
+		}
+	case "owner":
+		pred = OwnerPredicate(spec)
+		c.needsOwner = true
+	case "group":
+		pred = GroupPredicate(spec)
+		c.needsOwner = true
+	case "perm":
+		pred, err = PermPredicate(spec)
+	default:
+		return fmt.Errorf("unknown filter %q", name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.AddPredicate(name+"="+spec, pred)
+	return nil
+}
+
+func (c *Criteria) AddPredicate(spec string, pred Predicate) {
+	c.specs = append(c.specs, spec)
+	c.preds = append(c.preds, pred)
+}
+
+func (c *Criteria) RequireOwner() {
+	c.needsOwner = true
+}
+
+func (c *Criteria) NeedsOwner() bool {
+	return c != nil && c.needsOwner
+}
+
+func (c *Criteria) RequireBirth() {
+	c.needsBirth = true
+}
+
+func (c *Criteria) NeedsBirth() bool {
+	return c != nil && (c.needsBirth || c.usesTime && c.TimeField == "birth")
+}
+
+func (c *Criteria) Empty() bool {
+	return c == nil || len(c.preds) == 0
+}
+
+func (c *Criteria) Match(file structures.FileInfo) bool {
+	if c == nil {
+		return true
+	}
+	for _, pred := range c.preds {
+		if !pred(file) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Criteria) String() string {
+	if c == nil {
+		return ""
+	}
+	return strings.Join(c.specs, ",")
+}
@@ -0,0 +1,89 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Glob struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func CompileGlob(pattern string) (*Glob, error) {
+	expr, err := GlobToRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob %q: %v", pattern, err)
+	}
+	return &Glob{pattern: pattern, re: re}, nil
+}
+
+func (g *Glob) Match(relPath string) bool {
+	return g.re.MatchString(relPath)
+}
+
+func (g *Glob) String() string {
+	return g.pattern
+}
+
+// GlobToRegexp translates a slash-separated glob into an anchored regular
+// expression. Patterns without a slash match at any depth, "**" spans
+// directories and a trailing "/**" also matches the directory itself.
+func GlobToRegexp(pattern string) (string, error) {
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		return "", fmt.Errorf("empty glob pattern")
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case pattern[i:] == "/**":
+			expr.WriteString("(?:/.*)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated character class in glob %q", pattern)
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	expr.WriteString("/?$")
+	return expr.String(), nil
+}
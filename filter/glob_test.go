@@ -0,0 +1,61 @@
+package filter
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/gls/main.go", true},
+		{"*.go", "main.go.bak", false},
+		{"src/*.go", "src/main.go", true},
+		{"src/*.go", "src/sub/main.go", false},
+		{"src/*.go", "lib/src/main.go", false},
+		{"/src", "src", true},
+		{"/src", "lib/src", false},
+		{"build/", "build", true},
+		{"build/", "a/build", true},
+		{"**/test", "test", true},
+		{"**/test", "a/b/test", true},
+		{"**/test", "a/b/test/x", false},
+		{"src/**", "src", true},
+		{"src/**", "src/a/b.go", true},
+		{"src/**", "srcs/a", false},
+		{"a/**/z", "a/z", true},
+		{"a/**/z", "a/b/c/z", true},
+		{"a/**/z", "a/b/c/zz", false},
+		{"a**z", "a/b/z", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file/.txt", false},
+		{"[abc].txt", "b.txt", true},
+		{"[!abc].txt", "b.txt", false},
+		{"[!abc].txt", "d.txt", true},
+		{"\\*.txt", "*.txt", true},
+		{"\\*.txt", "a.txt", false},
+		{"a.b", "axb", false},
+	}
+
+	for _, tt := range tests {
+		expr, err := GlobToRegexp(tt.pattern)
+		if err != nil {
+			t.Fatalf("GlobToRegexp(%q): %v", tt.pattern, err)
+		}
+		if got := regexp.MustCompile(expr).MatchString(tt.path); got != tt.want {
+			t.Errorf("%q (%s) matching %q = %v, want %v", tt.pattern, expr, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestGlobToRegexpErrors(t *testing.T) {
+	for _, pattern := range []string{"", "/", "[abc", "src/[a"} {
+		if _, err := GlobToRegexp(pattern); err == nil {
+			t.Errorf("GlobToRegexp(%q) succeeded, want an error", pattern)
+		}
+	}
+}
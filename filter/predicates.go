@@ -0,0 +1,206 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+var sizeUnits = map[byte]int64{
+	'b': 1,
+	'k': 1 << 10,
+	'm': 1 << 20,
+	'g': 1 << 30,
+	't': 1 << 40,
+}
+
+func ParseSize(spec string) (int64, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+
+	lower := strings.ToLower(spec)
+	lower = strings.TrimSuffix(lower, "ib")
+	if lower == "" {
+		return 0, fmt.Errorf("invalid size %q", spec)
+	}
+	if len(lower) > 1 && strings.HasSuffix(lower, "b") {
+		if _, ok := sizeUnits[lower[len(lower)-2]]; ok {
+			lower = lower[:len(lower)-1]
+		}
+	}
+
+	multiplier := int64(1)
+	if unit, ok := sizeUnits[lower[len(lower)-1]]; ok {
+		multiplier = unit
+		lower = lower[:len(lower)-1]
+	}
+
+	value, err := strconv.ParseFloat(lower, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", spec)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+func SizePredicate(spec string) (Predicate, error) {
+	cmp := byte('=')
+	if strings.HasPrefix(spec, "+") || strings.HasPrefix(spec, "-") {
+		cmp = spec[0]
+		spec = spec[1:]
+	}
+
+	size, err := ParseSize(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(file structures.FileInfo) bool {
+		switch cmp {
+		case '+':
+			return file.RawSize > size
+		case '-':
+			return file.RawSize < size
+		default:
+			return file.RawSize == size
+		}
+	}, nil
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+var durationUnits = map[byte]time.Duration{
+	's': time.Second,
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+	'y': 365 * 24 * time.Hour,
+}
+
+// ParseTime accepts either an absolute date or an age such as "90d", which
+// is resolved relative to now.
+func ParseTime(spec string, now time.Time) (time.Time, error) {
+	spec = strings.TrimSpace(spec)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, spec, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	if len(spec) > 1 {
+		if unit, ok := durationUnits[spec[len(spec)-1]]; ok {
+			if n, err := strconv.ParseFloat(spec[:len(spec)-1], 64); err == nil && n >= 0 {
+				return now.Add(-time.Duration(n * float64(unit))), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q (use a date like 2025-01-01 or an age like 2d)", spec)
+}
+
+func OwnerPredicate(name string) Predicate {
+	return func(file structures.FileInfo) bool {
+		owner, _, _ := strings.Cut(file.UserAndGroup, ":")
+		return owner == name
+	}
+}
+
+func GroupPredicate(name string) Predicate {
+	return func(file structures.FileInfo) bool {
+		_, group, _ := strings.Cut(file.UserAndGroup, ":")
+		return group == name
+	}
+}
+
+func PermPredicate(spec string) (Predicate, error) {
+	mode := byte('=')
+	if strings.HasPrefix(spec, "/") || strings.HasPrefix(spec, "-") {
+		mode = spec[0]
+		spec = spec[1:]
+	}
+
+	bits, err := ParsePerm(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(file structures.FileInfo) bool {
+		perm := file.Mode.Perm()
+		switch mode {
+		case '/':
+			return bits == 0 || perm&bits != 0
+		case '-':
+			return perm&bits == bits
+		default:
+			return perm == bits
+		}
+	}, nil
+}
+
+func ParsePerm(spec string) (os.FileMode, error) {
+	if spec == "" {
+		return 0, fmt.Errorf("empty permission")
+	}
+
+	if octal, err := strconv.ParseUint(spec, 8, 32); err == nil {
+		if octal > 0o777 {
+			return 0, fmt.Errorf("invalid permission %q", spec)
+		}
+		return os.FileMode(octal), nil
+	}
+
+	var bits os.FileMode
+	for _, clause := range strings.Split(spec, ",") {
+		idx := strings.IndexAny(clause, "+=")
+		if idx < 0 {
+			return 0, fmt.Errorf("invalid permission %q (expected e.g. 644, u+x or o+w)", spec)
+		}
+
+		who, perms := clause[:idx], clause[idx+1:]
+		if who == "" {
+			who = "a"
+		}
+
+		var mask os.FileMode
+		for _, p := range perms {
+			switch p {
+			case 'r':
+				mask |= 0o4
+			case 'w':
+				mask |= 0o2
+			case 'x':
+				mask |= 0o1
+			default:
+				return 0, fmt.Errorf("invalid permission %q", spec)
+			}
+		}
+
+		for _, w := range who {
+			switch w {
+			case 'u':
+				bits |= mask << 6
+			case 'g':
+				bits |= mask << 3
+			case 'o':
+				bits |= mask
+			case 'a':
+				bits |= mask<<6 | mask<<3 | mask
+			default:
+				return 0, fmt.Errorf("invalid permission %q", spec)
+			}
+		}
+	}
+	return bits, nil
+}
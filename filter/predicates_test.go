@@ -0,0 +1,41 @@
+package filter
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		spec string
+		want int64
+	}{
+		{"0", 0},
+		{"100", 100},
+		{" 5k ", 5 << 10},
+		{"1k", 1 << 10},
+		{"1K", 1 << 10},
+		{"1kb", 1 << 10},
+		{"1KiB", 1 << 10},
+		{"1.5m", 3 << 19},
+		{"2G", 2 << 30},
+		{"1t", 1 << 40},
+		{"10b", 10},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSize(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSize(%q): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, want %d", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseSizeErrors(t *testing.T) {
+	for _, spec := range []string{"", "  ", "ib", "IB", "k", "b", "kib", "-1", "abc", "1x", "1.2.3k"} {
+		if _, err := ParseSize(spec); err == nil {
+			t.Errorf("ParseSize(%q) succeeded, want an error", spec)
+		}
+	}
+}
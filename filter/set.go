@@ -0,0 +1,132 @@
+package filter
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+type Predicate func(file structures.FileInfo) bool
+
+type Set struct {
+	Include []*Glob
+	Exclude []*Glob
+	Regex   []*regexp.Regexp
+}
+
+func (s *Set) AddInclude(pattern string) error {
+	glob, err := CompileGlob(pattern)
+	if err != nil {
+		return err
+	}
+	s.Include = append(s.Include, glob)
+	return nil
+}
+
+func (s *Set) AddExclude(pattern string) error {
+	glob, err := CompileGlob(pattern)
+	if err != nil {
+		return err
+	}
+	s.Exclude = append(s.Exclude, glob)
+	return nil
+}
+
+func (s *Set) AddRegex(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regex %q: %v", pattern, err)
+	}
+	s.Regex = append(s.Regex, re)
+	return nil
+}
+
+func (s *Set) Empty() bool {
+	return s == nil || len(s.Include) == 0 && len(s.Exclude) == 0 && len(s.Regex) == 0
+}
+
+func (s *Set) excluded(relPath string) bool {
+	for _, glob := range s.Exclude {
+		if glob.Match(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Set) Match(relPath string) bool {
+	if s.Empty() {
+		return true
+	}
+
+	relPath = filepath.ToSlash(relPath)
+	if s.excluded(relPath) {
+		return false
+	}
+
+	if len(s.Include) > 0 {
+		included := false
+		for _, glob := range s.Include {
+			if glob.Match(relPath) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	if len(s.Regex) > 0 {
+		for _, re := range s.Regex {
+			if re.MatchString(relPath) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func (s *Set) Prune(relDir string) bool {
+	if s.Empty() {
+		return false
+	}
+	return s.excluded(filepath.ToSlash(relDir))
+}
+
+func (s *Set) ForRoot(root string) Predicate {
+	return func(file structures.FileInfo) bool {
+		return s.Match(RelPath(root, file.Path))
+	}
+}
+
+func (s *Set) String() string {
+	if s.Empty() {
+		return ""
+	}
+
+	var parts []string
+	for _, glob := range s.Include {
+		parts = append(parts, "+"+glob.String())
+	}
+	for _, glob := range s.Exclude {
+		parts = append(parts, "-"+glob.String())
+	}
+	for _, re := range s.Regex {
+		parts = append(parts, "~"+re.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+func RelPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,93 @@
+package finder
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rinimisini112/gls/structures"
+)
+
+const DefaultCacheTTL = 5 * time.Minute
+
+type CacheStats struct {
+	Hits    int64
+	Misses  int64
+	Expired int64
+	Stale   int64
+	Entries int
+}
+
+// cachedSearch remembers the mtime of every directory the search read, so a
+// hit can be checked against the tree before it is trusted. Changes that do
+// not touch a directory's mtime, such as a file growing in place, are only
+// picked up once the entry expires.
+type cachedSearch struct {
+	results []structures.FileInfo
+	created time.Time
+	dirs    map[string]time.Time
+}
+
+type searchCache struct {
+	entries *lru.Cache
+	ttl     time.Duration
+	hits    atomic.Int64
+	misses  atomic.Int64
+	expired atomic.Int64
+	stale   atomic.Int64
+}
+
+var results *searchCache
+var resultsOnce sync.Once
+
+func searchResults() *searchCache {
+	resultsOnce.Do(func() {
+		entries, _ := lru.New(128)
+		results = &searchCache{entries: entries, ttl: DefaultCacheTTL}
+	})
+	return results
+}
+
+func SearchCacheStats() CacheStats {
+	c := searchResults()
+	return CacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Expired: c.expired.Load(),
+		Stale:   c.stale.Load(),
+		Entries: c.entries.Len(),
+	}
+}
+
+func (c *searchCache) get(key string) ([]structures.FileInfo, bool) {
+	value, ok := c.entries.Get(key)
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	entry := value.(*cachedSearch)
+	if c.ttl > 0 && time.Since(entry.created) > c.ttl {
+		c.entries.Remove(key)
+		c.expired.Add(1)
+		return nil, false
+	}
+
+	for dir, modTime := range entry.dirs {
+		info, err := os.Stat(dir)
+		if err != nil || !info.ModTime().Equal(modTime) {
+			c.entries.Remove(key)
+			c.stale.Add(1)
+			return nil, false
+		}
+	}
+
+	c.hits.Add(1)
+	return entry.results, true
+}
+
+func (c *searchCache) add(key string, entry *cachedSearch) {
+	c.entries.Add(key, entry)
+}
@@ -0,0 +1,150 @@
+package finder
+
+import (
+	"unicode"
+)
+
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+
+	bonusBoundaryWhite = 10
+	bonusPathSeparator = 9
+	bonusBoundary      = 8
+	bonusCamel         = 7
+	bonusConsecutive   = 4
+	bonusFirstCharMult = 2
+)
+
+const negInf = -1 << 30
+
+func charBonus(prev, cur rune) int {
+	switch {
+	case prev == 0:
+		return bonusBoundaryWhite
+	case prev == '/':
+		return bonusPathSeparator
+	case prev == ' ' || prev == '_' || prev == '-' || prev == '.':
+		return bonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return bonusCamel
+	case unicode.IsLetter(prev) && unicode.IsDigit(cur):
+		return bonusCamel
+	}
+	return 0
+}
+
+func hasUpper(s []rune) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// FuzzyMatch scores pattern as a subsequence of text, fzf style. Matching
+// is case-insensitive unless the pattern contains an upper-case letter.
+// It returns the best score and the rune offsets of the matched characters.
+func FuzzyMatch(pattern, text string) (int, []int, bool) {
+	p := []rune(pattern)
+	t := []rune(text)
+	m, n := len(p), len(t)
+	if m == 0 {
+		return 0, nil, true
+	}
+	if m > n {
+		return 0, nil, false
+	}
+
+	caseSensitive := hasUpper(p)
+	fold := func(r rune) rune {
+		if caseSensitive {
+			return r
+		}
+		return unicode.ToLower(r)
+	}
+
+	for i, j := 0, 0; i < m; j++ {
+		if j == n {
+			return 0, nil, false
+		}
+		if fold(t[j]) == fold(p[i]) {
+			i++
+		}
+	}
+
+	bonus := make([]int, n)
+	var prev rune
+	for j, r := range t {
+		bonus[j] = charBonus(prev, r)
+		prev = r
+	}
+
+	score := make([][]int, m)
+	back := make([][]int, m)
+	chunk := make([][]int, m)
+	for i := range score {
+		score[i] = make([]int, n)
+		back[i] = make([]int, n)
+		chunk[i] = make([]int, n)
+	}
+
+	for i := 0; i < m; i++ {
+		run, runArg := negInf, -1
+		for j := 0; j < n; j++ {
+			if i > 0 && j >= 2 {
+				if score[i-1][j-2] > negInf && score[i-1][j-2]+scoreGapStart > run+scoreGapExtension {
+					run, runArg = score[i-1][j-2]+scoreGapStart, j-2
+				} else if run > negInf {
+					run += scoreGapExtension
+				}
+			}
+
+			score[i][j] = negInf
+			if fold(t[j]) != fold(p[i]) {
+				continue
+			}
+
+			if i == 0 {
+				score[i][j] = scoreMatch + bonus[j]*bonusFirstCharMult
+				back[i][j] = -1
+				chunk[i][j] = bonus[j]
+				continue
+			}
+
+			best, arg, chunkBonus := run+bonus[j], runArg, bonus[j]
+			if j >= 1 && score[i-1][j-1] > negInf {
+				carried := max(bonusConsecutive, bonus[j], chunk[i-1][j-1])
+				if consecutive := score[i-1][j-1] + carried; consecutive >= best {
+					best, arg, chunkBonus = consecutive, j-1, carried
+				}
+			}
+			if best <= negInf/2 {
+				continue
+			}
+
+			score[i][j] = scoreMatch + best
+			back[i][j] = arg
+			chunk[i][j] = chunkBonus
+		}
+	}
+
+	bestScore, end := negInf, -1
+	for j := 0; j < n; j++ {
+		if score[m-1][j] > bestScore {
+			bestScore, end = score[m-1][j], j
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	positions := make([]int, m)
+	for i, j := m-1, end; i >= 0; i-- {
+		positions[i] = j
+		j = back[i][j]
+	}
+	return bestScore, positions, true
+}
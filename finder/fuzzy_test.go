@@ -0,0 +1,117 @@
+package finder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{"", "anything", true, nil},
+		{"abc", "abc", true, []int{0, 1, 2}},
+		{"abc", "a/b/c", true, []int{0, 2, 4}},
+		{"abc", "acb", false, nil},
+		{"abcd", "abc", false, nil},
+		{"mgo", "cmd/main.go", true, []int{4, 9, 10}},
+		{"MAIN", "main.go", false, nil},
+		{"main", "MAIN.go", true, []int{0, 1, 2, 3}},
+		{"Main", "src/Main.java", true, []int{4, 5, 6, 7}},
+		{"fb", "foo/bar", true, []int{0, 4}},
+	}
+
+	for _, tt := range tests {
+		_, positions, ok := FuzzyMatch(tt.pattern, tt.text)
+		if ok != tt.ok {
+			t.Errorf("FuzzyMatch(%q, %q) ok = %v, want %v", tt.pattern, tt.text, ok, tt.ok)
+			continue
+		}
+		if ok && !slices.Equal(positions, tt.positions) {
+			t.Errorf("FuzzyMatch(%q, %q) positions = %v, want %v", tt.pattern, tt.text, positions, tt.positions)
+		}
+	}
+}
+
+// TestFuzzyRanking checks that for each pattern the better candidate scores
+// strictly higher than the worse one.
+func TestFuzzyRanking(t *testing.T) {
+	tests := []struct {
+		pattern string
+		better  string
+		worse   string
+		reason  string
+	}{
+		{"main", "main.go", "my_amazing.go", "consecutive beats scattered"},
+		{"fb", "foo/bar", "afxbx", "word starts beat mid-word"},
+		{"bar", "foo/bar.go", "foobar.go", "after a separator beats mid-word"},
+		{"sc", "SearchCache", "sxxxxxxxxc", "camel humps beat a long gap"},
+		{"abc", "abc", "a-b-c", "no gaps beats gaps"},
+		{"go", "go.mod", "xgo.mod", "start of text beats later"},
+		{"ab", "ab", "axxxxxxxxxxb", "short gap beats long gap"},
+	}
+
+	for _, tt := range tests {
+		better, _, ok := FuzzyMatch(tt.pattern, tt.better)
+		if !ok {
+			t.Fatalf("FuzzyMatch(%q, %q) did not match", tt.pattern, tt.better)
+		}
+		worse, _, ok := FuzzyMatch(tt.pattern, tt.worse)
+		if !ok {
+			t.Fatalf("FuzzyMatch(%q, %q) did not match", tt.pattern, tt.worse)
+		}
+		if better <= worse {
+			t.Errorf("%s: %q scored %d for %q, not above %d for %q",
+				tt.reason, tt.pattern, better, tt.better, worse, tt.worse)
+		}
+	}
+}
+
+// TestFuzzySearchIgnoresRoot checks that only the path below the start
+// directory is scored, and that positions still point into the full path.
+func TestFuzzySearchIgnoresRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "projectx")
+	for _, dir := range []string{"a", "b"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"a/main.go", "b/zzz.md"} {
+		if err := os.WriteFile(filepath.Join(root, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	search := func(query string) []structures.FileInfo {
+		var found []structures.FileInfo
+		err := StreamContext(context.Background(), query, root, Options{Fuzzy: true}, func(file structures.FileInfo) {
+			found = append(found, file)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return found
+	}
+
+	if found := search("pjx"); len(found) != 0 {
+		t.Errorf("query matching only the root found %d entries", len(found))
+	}
+
+	found := search("zzz")
+	if len(found) != 1 {
+		t.Fatalf("found %d entries for %q, want 1", len(found), "zzz")
+	}
+	file := found[0]
+	start := len([]rune(file.Path)) - len("zzz.md")
+	if want := []int{start, start + 1, start + 2}; !slices.Equal(file.MatchPositions, want) {
+		t.Errorf("positions = %v, want %v in %q", file.MatchPositions, want, file.Path)
+	}
+}
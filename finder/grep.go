@@ -0,0 +1,103 @@
+package finder
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+const (
+	DefaultGrepMaxSize = 10 << 20
+	binarySniffSize    = 8000
+	maxLineSize        = 1 << 20
+)
+
+type Grep struct {
+	Pattern    string
+	Literal    bool
+	IgnoreCase bool
+	MaxSize    int64
+	re         *regexp.Regexp
+}
+
+func NewGrep(pattern string, literal, ignoreCase bool, maxSize int64) (*Grep, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("grep pattern must not be empty")
+	}
+
+	expr := pattern
+	if literal {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grep pattern %q: %v", pattern, err)
+	}
+
+	return &Grep{
+		Pattern:    pattern,
+		Literal:    literal,
+		IgnoreCase: ignoreCase,
+		MaxSize:    maxSize,
+		re:         re,
+	}, nil
+}
+
+func (g *Grep) String() string {
+	if g == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s|%t|%t|%d", g.Pattern, g.Literal, g.IgnoreCase, g.MaxSize)
+}
+
+// Scan returns the matching lines of path. Binary files and files larger than
+// MaxSize yield no matches.
+func (g *Grep) Scan(path string, size int64) ([]structures.LineMatch, error) {
+	if g.MaxSize > 0 && size > g.MaxSize {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReaderSize(f, binarySniffSize)
+	head, err := reader.Peek(binarySniffSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil, nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var matches []structures.LineMatch
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		spans := g.re.FindAllStringIndex(line, -1)
+		if len(spans) == 0 {
+			continue
+		}
+
+		match := structures.LineMatch{Line: lineNo, Text: line}
+		for _, span := range spans {
+			match.Spans = append(match.Spans, [2]int{span[0], span[1]})
+		}
+		matches = append(matches, match)
+	}
+
+	return matches, scanner.Err()
+}
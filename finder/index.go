@@ -0,0 +1,287 @@
+package finder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const indexVersion = 2
+
+// IndexRecord only keeps what the walker needs to list a directory. Sizes,
+// times and owners change without touching the parent directory, so they are
+// always read from the filesystem for the entries a search matches.
+type IndexRecord struct {
+	Name string
+	Type os.FileMode
+}
+
+type IndexDir struct {
+	ModTime time.Time
+	Entries []IndexRecord
+}
+
+// Index is an on-disk snapshot of a tree's directory listings. Search reads
+// a directory from the index while its mtime is unchanged and falls back to
+// the filesystem otherwise.
+type Index struct {
+	Version int
+	Root    string
+	Built   time.Time
+	Dirs    map[string]IndexDir
+	mu      sync.RWMutex
+	cwd     string
+}
+
+// indexedEntry serves an IndexRecord as the os.DirEntry the walker expects.
+// Info stats the file, so only entries that are actually used cost a syscall.
+type indexedEntry struct {
+	dir string
+	r   *IndexRecord
+}
+
+func (e indexedEntry) Name() string               { return e.r.Name }
+func (e indexedEntry) IsDir() bool                { return e.r.Type.IsDir() }
+func (e indexedEntry) Type() os.FileMode          { return e.r.Type }
+func (e indexedEntry) Info() (os.FileInfo, error) { return os.Lstat(filepath.Join(e.dir, e.r.Name)) }
+
+func IndexDirPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gls", "index"), nil
+}
+
+func IndexPath(root string) (string, error) {
+	dir, err := IndexDirPath()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(root))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".gob"), nil
+}
+
+func LoadIndex(root string) (*Index, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	path, err := IndexPath(root)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var idx Index
+	if err := gob.NewDecoder(f).Decode(&idx); err != nil {
+		return nil, err
+	}
+	if idx.Version != indexVersion || idx.Root != root {
+		return nil, os.ErrNotExist
+	}
+	idx.cwd, _ = os.Getwd()
+	return &idx, nil
+}
+
+// FindIndex returns the index covering dir: one built for dir itself or for
+// the nearest ancestor that has one.
+func FindIndex(dir string) (*Index, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for path := abs; ; path = filepath.Dir(path) {
+		idx, err := LoadIndex(path)
+		if err == nil {
+			return idx, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if path == filepath.Dir(path) {
+			return nil, os.ErrNotExist
+		}
+	}
+}
+
+func (idx *Index) Save() error {
+	path, err := IndexPath(idx.Root)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	idx.mu.RLock()
+	err = gob.NewEncoder(f).Encode(idx)
+	idx.mu.RUnlock()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Entries returns the indexed listing of dir and its recorded mtime if the
+// directory has not been modified since.
+func (idx *Index) Entries(dir string) ([]os.DirEntry, time.Time, bool) {
+	if idx == nil {
+		return nil, time.Time{}, false
+	}
+
+	idx.mu.RLock()
+	cached, ok := idx.Dirs[idx.abs(dir)]
+	idx.mu.RUnlock()
+	if !ok {
+		return nil, time.Time{}, false
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil || !info.ModTime().Equal(cached.ModTime) {
+		return nil, time.Time{}, false
+	}
+
+	entries := make([]os.DirEntry, len(cached.Entries))
+	for i := range cached.Entries {
+		entries[i] = indexedEntry{dir: dir, r: &cached.Entries[i]}
+	}
+	return entries, cached.ModTime, true
+}
+
+// abs resolves walker paths, which are relative when the search root is, to
+// the absolute keys the index is stored under.
+func (idx *Index) abs(dir string) string {
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(idx.cwd, dir)
+}
+
+func (idx *Index) record(dir string, modTime time.Time, entries []os.DirEntry) {
+	indexed := IndexDir{ModTime: modTime, Entries: make([]IndexRecord, 0, len(entries))}
+	for _, entry := range entries {
+		indexed.Entries = append(indexed.Entries, IndexRecord{Name: entry.Name(), Type: entry.Type()})
+	}
+
+	idx.mu.Lock()
+	idx.Dirs[idx.abs(dir)] = indexed
+	idx.mu.Unlock()
+}
+
+// Refresh re-reads dir into the index and indexes any subdirectories it has
+// not seen yet. Subdirectories that disappeared are dropped, as is dir itself
+// when it no longer exists.
+func (idx *Index) Refresh(ctx context.Context, dir string, opts Options) error {
+	dir = idx.abs(dir)
+
+	info, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		idx.Forget(dir)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return &DirError{Path: dir, Err: err}
+	}
+
+	idx.mu.RLock()
+	previous := idx.Dirs[dir].Entries
+	idx.mu.RUnlock()
+	idx.record(dir, info.ModTime(), entries)
+
+	present := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		present[entry.Name()] = true
+	}
+	for _, old := range previous {
+		if old.Type.IsDir() && !present[old.Name] {
+			idx.Forget(filepath.Join(dir, old.Name))
+		}
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		sub := filepath.Join(dir, entry.Name())
+		idx.mu.RLock()
+		_, known := idx.Dirs[sub]
+		idx.mu.RUnlock()
+		if !known {
+			errs = append(errs, walk(ctx, sub, opts, func(Entry) {}, idx.record))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Forget drops dir and everything below it from the index.
+func (idx *Index) Forget(dir string) {
+	dir = idx.abs(dir)
+	prefix := dir + string(filepath.Separator)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for path := range idx.Dirs {
+		if path == dir || strings.HasPrefix(path, prefix) {
+			delete(idx.Dirs, path)
+		}
+	}
+}
+
+// Paths lists the directories currently in the index.
+func (idx *Index) Paths() []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	paths := make([]string, 0, len(idx.Dirs))
+	for path := range idx.Dirs {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// BuildIndex walks root and records every directory it reads. Passing the
+// previous index turns this into an update: unchanged directories are copied
+// over without being re-read and directories that no longer exist drop out.
+func BuildIndex(ctx context.Context, root string, prev *Index, opts Options) (*Index, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &Index{Version: indexVersion, Root: root, Built: time.Now(), Dirs: make(map[string]IndexDir)}
+	idx.cwd, _ = os.Getwd()
+	opts.Index = prev
+	err = walk(ctx, root, opts, func(Entry) {}, idx.record)
+	return idx, err
+}
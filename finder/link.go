@@ -0,0 +1,37 @@
+package finder
+
+import (
+	"os"
+	"syscall"
+)
+
+type FileID struct {
+	Dev uint64
+	Ino uint64
+}
+
+func FileIDOf(info os.FileInfo) (FileID, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return FileID{}, false
+	}
+	return FileID{Dev: uint64(stat.Dev), Ino: uint64(stat.Ino)}, true
+}
+
+// ResolveLink reports the target of a symlink and whether it is broken. When
+// follow is set and the target exists, its info replaces the link's own.
+func ResolveLink(path string, info os.FileInfo, follow bool) (os.FileInfo, string, bool) {
+	if info.Mode()&os.ModeSymlink == 0 {
+		return info, "", false
+	}
+
+	target, _ := os.Readlink(path)
+	targetInfo, err := os.Stat(path)
+	if err != nil {
+		return info, target, true
+	}
+	if follow {
+		return targetInfo, target, false
+	}
+	return info, target, false
+}
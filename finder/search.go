@@ -1,30 +1,33 @@
 package finder
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/user"
-	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/dustin/go-humanize"
 	lru "github.com/hashicorp/golang-lru"
+	"github.com/rinimisini112/gls/filter"
 	"github.com/rinimisini112/gls/structures"
+	"github.com/rinimisini112/gls/sysinfo"
 )
 
 var userCache *lru.Cache
 var groupCache *lru.Cache
-var fileCache *lru.Cache
 var once sync.Once
 
 func initCaches() {
 	once.Do(func() {
 		userCache, _ = lru.New(128)
 		groupCache, _ = lru.New(128)
-		fileCache, _ = lru.New(128)
 	})
 }
 
@@ -64,129 +67,280 @@ func getGroupName(gid uint32) string {
 	return grp.Name
 }
 
-func CalculateDirSize(dirPath string) int64 {
-	var totalSize int64 = 0
+func UserAndGroup(uid, gid uint32) string {
+	return fmt.Sprintf("%s:%s", getUserName(uid), getGroupName(gid))
+}
 
-	info, err := os.Stat(dirPath)
-	if err != nil {
-		fmt.Printf("❌ Error accessing %s: %v\n", dirPath, err)
-		return 0
-	}
-	if !info.IsDir() {
-		fmt.Printf("⚠️ Warning: %s is not a directory!\n", dirPath)
-		return 0
-	}
+type SizeOptions struct {
+	DiskUsage     bool
+	OneFileSystem bool
+}
 
-	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			fmt.Printf("⚠️ Skipping file %s due to error: %v\n", path, err)
-			return nil
-		}
-		if !info.IsDir() {
-			totalSize += info.Size()
-		}
-		return nil
-	})
+func (o SizeOptions) String() string {
+	return fmt.Sprintf("%t|%t", o.DiskUsage, o.OneFileSystem)
+}
 
-	if err != nil {
-		fmt.Printf("❌ Error calculating size for %s: %v\n", dirPath, err)
-		return 0
-	}
+type Options struct {
+	FilterType       string
+	WithUserAndGroup bool
+	WithBirthTime    bool
+	FullDirSize      bool
+	Filter           *filter.Set
+	Criteria         *filter.Criteria
+	NoIgnore         bool
+	NoHidden         bool
+	Size             SizeOptions
+	Sizes            *SizeScanner
+	Fuzzy            bool
+	Follow           bool
+	Grep             *Grep
+	Index            *Index
+	NoCache          bool
+	Workers          int
+}
 
-	return totalSize
+func (o Options) key() string {
+	var timeField string
+	if o.Criteria != nil {
+		timeField = o.Criteria.TimeField
+	}
+	return fmt.Sprintf("%s|%t|%t|%t|%s|%s|%s|%t|%t|%s|%t|%t|%s",
+		o.FilterType, o.WithUserAndGroup, o.WithBirthTime, o.FullDirSize, o.Filter, o.Criteria, timeField,
+		o.NoIgnore, o.NoHidden, o.Size, o.Fuzzy, o.Follow, o.Grep)
 }
 
-func searchFiles(
-	rootDir,
-	query string,
-	results chan<- structures.FileInfo,
-	wg *sync.WaitGroup,
-	withGroupAndUser bool,
-	fullDirSize bool,
-) {
-	defer wg.Done()
+func (o Options) matches(file structures.FileInfo) bool {
+	return file.IsType(o.FilterType) && o.Criteria.Match(file)
+}
 
-	entries, err := os.ReadDir(rootDir)
+func newSearchResult(e Entry, opts Options) (structures.FileInfo, error) {
+	info, err := e.Dir.Info()
 	if err != nil {
-		return
+		return structures.FileInfo{}, err
 	}
 
-	for _, entry := range entries {
-		fullPath := filepath.Join(rootDir, entry.Name())
+	isLink := info.Mode()&os.ModeSymlink != 0
+	info, target, broken := ResolveLink(e.Path, info, opts.Follow)
 
-		info, err := entry.Info()
-		if err != nil {
-			continue
-		}
+	var userAndGroup string
+	if opts.WithUserAndGroup {
+		stat := info.Sys().(*syscall.Stat_t)
+		userAndGroup = UserAndGroup(stat.Uid, stat.Gid)
+	}
 
-		var userAndGroup string
-		if withGroupAndUser {
-			userAndGroup = fmt.Sprintf("%s:%s", getUserName(info.Sys().(*syscall.Stat_t).Uid), getGroupName(info.Sys().(*syscall.Stat_t).Gid))
-		}
+	size := info.Size()
 
-		if strings.Contains(strings.ToLower(entry.Name()), strings.ToLower(query)) {
-			results <- structures.FileInfo{
-				Name:  entry.Name(),
-				Path:  fullPath,
-				IsDir: entry.IsDir(),
-				Size: func() string {
-					if entry.IsDir() && fullDirSize {
-						return humanize.Bytes(uint64(CalculateDirSize(fullPath)))
-					}
-					return humanize.Bytes(uint64(info.Size()))
-				}(),
-				ModTime: info.ModTime().Format(time.RFC822),
-				UserAndGroup: func() string {
-					if withGroupAndUser && userAndGroup != "" {
-						return userAndGroup
-					}
-					return ""
-				}(),
-				Permissions: info.Mode().String(),
-			}
-		}
+	atime, ctime := sysinfo.Times(info)
+	inode := sysinfo.InodeOf(info)
 
-		if entry.IsDir() {
-			wg.Add(1)
-			go searchFiles(fullPath, query, results, wg, withGroupAndUser, fullDirSize)
-		}
+	var birth time.Time
+	if opts.WithBirthTime {
+		birth = sysinfo.BirthTime(e.Path, info)
 	}
+
+	return structures.FileInfo{
+		Name:         e.Dir.Name(),
+		Path:         e.Path,
+		Depth:        strings.Count(e.RelPath, "/") + 1,
+		IsDir:        info.IsDir(),
+		Kind:         structures.KindOf(info.Mode()),
+		IsLink:       isLink,
+		Broken:       broken,
+		LinkTarget:   target,
+		Hidden:       e.Dir.Name()[0] == '.',
+		Size:         humanize.Bytes(uint64(size)),
+		RawSize:      size,
+		ModTime:      info.ModTime(),
+		AccessTime:   atime,
+		ChangeTime:   ctime,
+		BirthTime:    birth,
+		UserAndGroup: userAndGroup,
+		Permissions:  info.Mode().String(),
+		Mode:         info.Mode(),
+		Inode:        inode.Ino,
+		Links:        inode.Nlink,
+		Blocks:       inode.Blocks,
+		Dev:          inode.Dev,
+		Rdev:         inode.Rdev,
+	}, nil
 }
 
-func Search(query, startDir, filterType string, withUserAndGroup bool, fullDirSize bool) []structures.FileInfo {
-	startTime := time.Now()
-	initCaches()
+func StreamContext(ctx context.Context, query, startDir string, opts Options, emit func(structures.FileInfo)) error {
+	return streamContext(ctx, query, startDir, opts, emit, nil)
+}
 
-	cacheKey := fmt.Sprintf("%s|%s|%s", query, startDir, filterType)
+func streamContext(
+	ctx context.Context,
+	query, startDir string,
+	opts Options,
+	emit func(structures.FileInfo),
+	onDir func(string, time.Time, []os.DirEntry),
+) error {
+	initCaches()
 
-	if cachedResult, found := fileCache.Get(cacheKey); found {
-		fmt.Println("✅ Returning cached search results")
-		return cachedResult.([]structures.FileInfo)
+	if opts.FullDirSize && opts.Sizes == nil {
+		opts.Sizes = NewSizeScanner(opts.Size)
 	}
 
+	lowerQuery := strings.ToLower(query)
 	results := make(chan structures.FileInfo, 100)
-	var wg sync.WaitGroup
-
-	wg.Add(1)
-	go searchFiles(startDir, query, results, &wg, withUserAndGroup, fullDirSize)
+	walkErr := make(chan error, 1)
+
+	var scanMu sync.Mutex
+	var scanErrs []error
+	fail := func(err error) {
+		scanMu.Lock()
+		defer scanMu.Unlock()
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			scanErrs = append(scanErrs, joined.Unwrap()...)
+			return
+		}
+		scanErrs = append(scanErrs, err)
+	}
 
 	go func() {
-		wg.Wait()
+		walkErr <- walk(ctx, startDir, opts, func(e Entry) {
+			var score int
+			var positions []int
+			if opts.Fuzzy {
+				// Score the path below the start directory, so a query that
+				// only matches the root does not match everything under it.
+				var ok bool
+				if score, positions, ok = FuzzyMatch(query, e.RelPath); !ok {
+					return
+				}
+				offset := utf8.RuneCountInString(e.Path) - utf8.RuneCountInString(e.RelPath)
+				for i := range positions {
+					positions[i] += offset
+				}
+			} else if !strings.Contains(strings.ToLower(e.Dir.Name()), lowerQuery) {
+				return
+			}
+			if !opts.Filter.Match(e.RelPath) {
+				return
+			}
+
+			file, err := newSearchResult(e, opts)
+			if err != nil {
+				return
+			}
+			file.Score = score
+			file.MatchPositions = positions
+
+			if file.IsDir && opts.FullDirSize {
+				size, err := opts.Sizes.DirSize(e.Path)
+				if err != nil {
+					fail(err)
+				}
+				file.RawSize = size
+				file.Size = humanize.Bytes(uint64(size))
+			}
+
+			if !opts.matches(file) {
+				return
+			}
+			if opts.Grep != nil {
+				if !file.Mode.IsRegular() {
+					return
+				}
+				var err error
+				if file.Lines, err = opts.Grep.Scan(e.Path, file.RawSize); err != nil {
+					fail(&DirError{Path: e.Path, Err: err})
+				}
+				if len(file.Lines) == 0 {
+					return
+				}
+			}
+
+			select {
+			case results <- file:
+			case <-ctx.Done():
+			}
+		}, onDir)
 		close(results)
 	}()
 
-	var matches []structures.FileInfo
+	var ranked []structures.FileInfo
 	for result := range results {
-		if filterType == "dir" && !result.IsDir {
+		if opts.Fuzzy {
+			ranked = append(ranked, result)
 			continue
 		}
-		if filterType == "file" && result.IsDir {
-			continue
+		emit(result)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return len(ranked[i].Path) < len(ranked[j].Path)
+	})
+	for _, result := range ranked {
+		emit(result)
+	}
+
+	err := <-walkErr
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return errors.Join(append(scanErrs, joined.Unwrap()...)...)
+	}
+	return errors.Join(append(scanErrs, err)...)
+}
+
+// SearchContext collects the results of a search, reusing an earlier
+// identical search while none of the directories it read have changed.
+// Content searches are never cached since file edits do not show up in
+// directory mtimes.
+func SearchContext(ctx context.Context, query, startDir string, opts Options) ([]structures.FileInfo, error) {
+	matches, _, err := searchContext(ctx, query, startDir, opts)
+	return matches, err
+}
+
+func searchContext(ctx context.Context, query, startDir string, opts Options) ([]structures.FileInfo, bool, error) {
+	cache := searchResults()
+	cacheKey := searchKey(query, startDir, opts)
+	useCache := !opts.NoCache && opts.Grep == nil
+
+	if useCache {
+		if cached, found := cache.get(cacheKey); found {
+			return cached, true, nil
 		}
-		matches = append(matches, result)
 	}
 
-	fileCache.Add(cacheKey, matches)
+	var matches []structures.FileInfo
+	var mu sync.Mutex
+	dirs := make(map[string]time.Time)
+
+	var onDir func(string, time.Time, []os.DirEntry)
+	if useCache {
+		onDir = func(dir string, modTime time.Time, _ []os.DirEntry) {
+			mu.Lock()
+			dirs[dir] = modTime
+			mu.Unlock()
+		}
+	}
+
+	err := streamContext(ctx, query, startDir, opts, func(file structures.FileInfo) {
+		matches = append(matches, file)
+	}, onDir)
+
+	if useCache && ctx.Err() == nil && err == nil {
+		cache.add(cacheKey, &cachedSearch{results: matches, created: time.Now(), dirs: dirs})
+	}
+	return matches, false, err
+}
+
+func searchKey(query, startDir string, opts Options) string {
+	return fmt.Sprintf("%s|%s|%s", query, startDir, opts.key())
+}
+
+func Search(query, startDir string, opts Options) []structures.FileInfo {
+	startTime := time.Now()
+
+	matches, cached, _ := searchContext(context.Background(), query, startDir, opts)
+	if cached {
+		fmt.Println("✅ Returning cached search results")
+	}
+
 	fmt.Printf("🔍 Search took %s\n", time.Since(startTime))
 	return matches
 }
@@ -0,0 +1,380 @@
+package finder
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rinimisini112/gls/sysinfo"
+)
+
+type linkedFile struct {
+	Dev    uint64 `json:"dev"`
+	Ino    uint64 `json:"ino"`
+	Size   int64  `json:"size"`
+	Blocks int64  `json:"blocks"`
+}
+
+// sizeEntry is what the scanner remembers about a single directory. It stays
+// valid for as long as the directory's mtime does, which covers entries being
+// added, removed or renamed but not files growing in place. Seen records the
+// last run that visited it, so entries for vanished trees eventually expire.
+type sizeEntry struct {
+	ModTime  int64        `json:"mtime"`
+	Seen     int64        `json:"seen"`
+	Size     int64        `json:"size"`
+	Blocks   int64        `json:"blocks"`
+	Links    []linkedFile `json:"links,omitempty"`
+	Children []string     `json:"children,omitempty"`
+	Items    int64        `json:"items"`
+}
+
+const (
+	sizeCacheVersion = 2
+	sizeCacheMaxAge  = 30 * 24 * time.Hour
+	sizeCacheTouch   = 24 * time.Hour
+)
+
+type sizeCache struct {
+	Version int                  `json:"version"`
+	Dirs    map[string]sizeEntry `json:"dirs"`
+}
+
+type subtree struct {
+	size  int64
+	items int64
+	links map[FileID]int64
+}
+
+type DirUsage struct {
+	Size  int64
+	Items int64
+}
+
+// memoEntry is a finished subtree total. It is only trusted within the
+// generation that computed it, since a change deeper down leaves dir's own
+// mtime alone.
+type memoEntry struct {
+	gen     int64
+	modTime int64
+	tree    subtree
+}
+
+// SizeScanner computes directory totals, walking each subtree once and
+// sharing the work between calls. Per-directory results are kept in memory
+// and can be saved to disk so later runs only re-read changed directories.
+type SizeScanner struct {
+	opts    SizeOptions
+	sem     chan struct{}
+	mu      sync.Mutex
+	entries map[string]sizeEntry
+	memo    map[string]memoEntry
+	gen     int64
+	roots   map[string]bool
+	visited map[string]bool
+	dirty   bool
+	scanned atomic.Int64
+}
+
+func NewSizeScanner(opts SizeOptions) *SizeScanner {
+	return &SizeScanner{
+		opts:    opts,
+		sem:     make(chan struct{}, defaultWorkers()),
+		entries: make(map[string]sizeEntry),
+		memo:    make(map[string]memoEntry),
+		roots:   make(map[string]bool),
+		visited: make(map[string]bool),
+	}
+}
+
+func SizeCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gls", "sizes.json"), nil
+}
+
+func (s *SizeScanner) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var cache sizeCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return err
+	}
+	if cache.Version != sizeCacheVersion {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for path, entry := range cache.Dirs {
+		s.entries[path] = entry
+	}
+	return nil
+}
+
+// Save writes the per-directory entries back to path. Entries below a sized
+// directory that this run did not reach are gone from disk and are dropped,
+// as are entries no run has visited for sizeCacheMaxAge.
+func (s *SizeScanner) Save(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-sizeCacheMaxAge).Unix()
+	for dir, entry := range s.entries {
+		if s.visited[dir] {
+			continue
+		}
+		if entry.Seen < cutoff || s.underRoot(dir) {
+			delete(s.entries, dir)
+			s.dirty = true
+		}
+	}
+
+	if !s.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(sizeCache{Version: sizeCacheVersion, Dirs: s.entries})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// Scanned reports how many directories have been read from disk so far.
+func (s *SizeScanner) Scanned() int64 {
+	return s.scanned.Load()
+}
+
+// Refresh makes later calls re-check every directory below the ones they
+// size instead of reusing totals from earlier calls. Callers that keep a
+// scanner across filesystem changes use it before each pass; directories
+// whose mtime is unchanged are still not re-read.
+func (s *SizeScanner) Refresh() {
+	s.mu.Lock()
+	s.gen++
+	s.mu.Unlock()
+}
+
+func (s *SizeScanner) underRoot(dir string) bool {
+	for path := dir; ; path = filepath.Dir(path) {
+		if s.roots[path] {
+			return true
+		}
+		if path == filepath.Dir(path) {
+			return false
+		}
+	}
+}
+
+func (s *SizeScanner) DirSize(dir string) (int64, error) {
+	usage, err := s.Usage(dir)
+	return usage.Size, err
+}
+
+func (s *SizeScanner) Usage(dir string) (DirUsage, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return DirUsage{}, err
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil {
+		return DirUsage{}, err
+	}
+	if !info.IsDir() {
+		return DirUsage{}, &DirError{Path: dir, Err: errors.New("not a directory")}
+	}
+
+	s.mu.Lock()
+	s.roots[abs] = true
+	s.mu.Unlock()
+
+	tree, err := s.scan(abs, info, sysinfo.InodeOf(info).Dev)
+	usage := DirUsage{Size: tree.size, Items: tree.items}
+	for _, size := range tree.links {
+		usage.Size += size
+	}
+	return usage, err
+}
+
+// DirSizes sizes several directories concurrently, returning totals in the
+// same order as dirs.
+func (s *SizeScanner) DirSizes(dirs []string) ([]int64, error) {
+	sizes := make([]int64, len(dirs))
+	errs := make([]error, len(dirs))
+
+	var wg sync.WaitGroup
+	for i, dir := range dirs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sizes[i], errs[i] = s.DirSize(dir)
+		}()
+	}
+	wg.Wait()
+
+	return sizes, errors.Join(errs...)
+}
+
+func (s *SizeScanner) scan(dir string, info os.FileInfo, rootDev uint64) (subtree, error) {
+	modTime := info.ModTime().UnixNano()
+
+	s.mu.Lock()
+	gen := s.gen
+	memo, ok := s.memo[dir]
+	s.mu.Unlock()
+	if ok && memo.gen == gen && memo.modTime == modTime {
+		return memo.tree, nil
+	}
+
+	entry, entryErr := s.entry(dir, info)
+
+	tree := subtree{size: entry.Size, items: entry.Items, links: make(map[FileID]int64)}
+	if s.opts.DiskUsage {
+		tree.size = entry.Blocks * 512
+	}
+	for _, link := range entry.Links {
+		size := link.Size
+		if s.opts.DiskUsage {
+			size = link.Blocks * 512
+		}
+		tree.links[FileID{Dev: link.Dev, Ino: link.Ino}] = size
+	}
+
+	results := make([]subtree, len(entry.Children))
+	errs := make([]error, len(entry.Children), len(entry.Children)+1)
+	var wg sync.WaitGroup
+
+	for i, name := range entry.Children {
+		path := filepath.Join(dir, name)
+		childInfo, err := os.Lstat(path)
+		if err != nil {
+			errs[i] = &DirError{Path: path, Err: err}
+			continue
+		}
+		if !childInfo.IsDir() {
+			continue
+		}
+		if s.opts.OneFileSystem && sysinfo.InodeOf(childInfo).Dev != rootDev {
+			continue
+		}
+
+		select {
+		case s.sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-s.sem }()
+				results[i], errs[i] = s.scan(path, childInfo, rootDev)
+			}()
+		default:
+			results[i], errs[i] = s.scan(path, childInfo, rootDev)
+		}
+	}
+	wg.Wait()
+
+	for _, child := range results {
+		tree.size += child.size
+		tree.items += child.items
+		for id, size := range child.links {
+			tree.links[id] = size
+		}
+	}
+
+	err := errors.Join(append(errs, entryErr)...)
+	if err == nil {
+		s.mu.Lock()
+		s.memo[dir] = memoEntry{gen: gen, modTime: modTime, tree: tree}
+		s.mu.Unlock()
+	}
+	return tree, err
+}
+
+func (s *SizeScanner) entry(dir string, info os.FileInfo) (sizeEntry, error) {
+	modTime := info.ModTime().UnixNano()
+
+	now := time.Now().Unix()
+
+	s.mu.Lock()
+	s.visited[dir] = true
+	cached, ok := s.entries[dir]
+	if ok && cached.ModTime == modTime {
+		if time.Duration(now-cached.Seen)*time.Second >= sizeCacheTouch {
+			cached.Seen = now
+			s.entries[dir] = cached
+			s.dirty = true
+		}
+		s.mu.Unlock()
+		return cached, nil
+	}
+	s.mu.Unlock()
+
+	entries, err := os.ReadDir(dir)
+	s.scanned.Add(1)
+
+	entry := sizeEntry{ModTime: modTime, Seen: now, Blocks: sysinfo.InodeOf(info).Blocks, Items: int64(len(entries))}
+	var errs []error
+	if err != nil {
+		errs = append(errs, &DirError{Path: dir, Err: err})
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			entry.Children = append(entry.Children, e.Name())
+			continue
+		}
+
+		fileInfo, err := e.Info()
+		if err != nil {
+			errs = append(errs, &DirError{Path: filepath.Join(dir, e.Name()), Err: err})
+			continue
+		}
+
+		inode := sysinfo.InodeOf(fileInfo)
+		if inode.Nlink > 1 {
+			entry.Links = append(entry.Links, linkedFile{
+				Dev:    inode.Dev,
+				Ino:    inode.Ino,
+				Size:   fileInfo.Size(),
+				Blocks: inode.Blocks,
+			})
+			continue
+		}
+		entry.Size += fileInfo.Size()
+		entry.Blocks += inode.Blocks
+	}
+
+	if len(errs) > 0 {
+		return entry, errors.Join(errs...)
+	}
+
+	s.mu.Lock()
+	s.entries[dir] = entry
+	s.dirty = true
+	s.mu.Unlock()
+	return entry, nil
+}
@@ -0,0 +1,238 @@
+package finder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/rinimisini112/gls/filter"
+	"github.com/rinimisini112/gls/ignore"
+)
+
+type Entry struct {
+	Path    string
+	RelPath string
+	Dir     os.DirEntry
+}
+
+type DirError struct {
+	Path string
+	Err  error
+}
+
+func (e *DirError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *DirError) Unwrap() error {
+	return e.Err
+}
+
+type dirJob struct {
+	path    string
+	rel     string
+	ignores *ignore.Matcher
+}
+
+type walker struct {
+	ctx     context.Context
+	root    string
+	opts    Options
+	visit   func(Entry)
+	onDir   func(dir string, modTime time.Time, entries []os.DirEntry)
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []dirJob
+	visited map[FileID]bool
+	pending int
+	errs    []error
+}
+
+func defaultWorkers() int {
+	return runtime.GOMAXPROCS(0) * 2
+}
+
+// walk visits every entry below root using a fixed pool of workers, so at
+// most opts.Workers directories are open at once. With opts.NoHidden, dot
+// entries are skipped and hidden directories are not entered. visit is called
+// concurrently and must be safe for concurrent use. Per-directory errors
+// are collected and returned joined together with any context error.
+func walk(ctx context.Context, root string, opts Options, visit func(Entry), onDir func(string, time.Time, []os.DirEntry)) error {
+	w := &walker{ctx: ctx, root: root, opts: opts, visit: visit, onDir: onDir, visited: make(map[FileID]bool)}
+	w.cond = sync.NewCond(&w.mu)
+	w.enter(root)
+
+	var ignores *ignore.Matcher
+	if !opts.NoIgnore {
+		ignores = ignore.New(root)
+	}
+	w.push(dirJob{path: root, ignores: ignores})
+
+	stop := context.AfterFunc(ctx, func() {
+		w.mu.Lock()
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	})
+	defer stop()
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultWorkers()
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, ok := w.pop()
+				if !ok {
+					return
+				}
+				w.readDir(job)
+				w.done()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		w.errs = append(w.errs, err)
+	}
+	return errors.Join(w.errs...)
+}
+
+func (w *walker) push(job dirJob) {
+	w.mu.Lock()
+	w.queue = append(w.queue, job)
+	w.pending++
+	w.cond.Signal()
+	w.mu.Unlock()
+}
+
+func (w *walker) pop() (dirJob, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for len(w.queue) == 0 && w.pending > 0 && w.ctx.Err() == nil {
+		w.cond.Wait()
+	}
+	if len(w.queue) == 0 || w.ctx.Err() != nil {
+		return dirJob{}, false
+	}
+
+	job := w.queue[len(w.queue)-1]
+	w.queue = w.queue[:len(w.queue)-1]
+	return job, true
+}
+
+func (w *walker) done() {
+	w.mu.Lock()
+	w.pending--
+	if w.pending == 0 {
+		w.cond.Broadcast()
+	}
+	w.mu.Unlock()
+}
+
+// enter records the directory at path as visited and reports whether it had
+// not been seen before. Only needed when following links, where a link can
+// lead back to an ancestor.
+func (w *walker) enter(path string) bool {
+	if !w.opts.Follow {
+		return true
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	id, ok := FileIDOf(info)
+	if !ok {
+		return true
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.visited[id] {
+		return false
+	}
+	w.visited[id] = true
+	return true
+}
+
+func (w *walker) fail(path string, err error) {
+	w.mu.Lock()
+	w.errs = append(w.errs, &DirError{Path: path, Err: err})
+	w.mu.Unlock()
+}
+
+// readEntries lists dir from the index when it is still fresh and from disk
+// otherwise.
+func (w *walker) readEntries(dir string) ([]os.DirEntry, error) {
+	entries, modTime, ok := w.opts.Index.Entries(dir)
+	if !ok {
+		if w.onDir != nil {
+			info, err := os.Stat(dir)
+			if err != nil {
+				return nil, err
+			}
+			modTime = info.ModTime()
+		}
+
+		var err error
+		if entries, err = os.ReadDir(dir); err != nil {
+			return entries, err
+		}
+	}
+
+	if w.onDir != nil {
+		w.onDir(dir, modTime, entries)
+	}
+	return entries, nil
+}
+
+func (w *walker) readDir(job dirJob) {
+	entries, err := w.readEntries(job.path)
+	if err != nil {
+		w.fail(job.path, err)
+		if len(entries) == 0 {
+			return
+		}
+	}
+
+	for _, entry := range entries {
+		if w.ctx.Err() != nil {
+			return
+		}
+		if w.opts.NoHidden && entry.Name()[0] == '.' {
+			continue
+		}
+
+		fullPath := filepath.Join(job.path, entry.Name())
+		relPath := filter.RelPath(w.root, fullPath)
+
+		isDir := entry.IsDir()
+		if w.opts.Follow && entry.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(fullPath); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+
+		if job.ignores.Ignored(relPath, isDir) {
+			continue
+		}
+
+		w.visit(Entry{Path: fullPath, RelPath: relPath, Dir: entry})
+
+		if isDir && !w.opts.Filter.Prune(relPath) && w.enter(fullPath) {
+			w.push(dirJob{path: fullPath, rel: relPath, ignores: job.ignores.Enter(fullPath, relPath)})
+		}
+	}
+}
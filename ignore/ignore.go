@@ -0,0 +1,245 @@
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rinimisini112/gls/filter"
+)
+
+var FileNames = []string{".gitignore", ".ignore", ".glsignore"}
+
+type rule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+type ruleSet struct {
+	base   string
+	prefix string
+	rules  []rule
+}
+
+type Matcher struct {
+	parent *Matcher
+	sets   []ruleSet
+}
+
+func New(root string) *Matcher {
+	m := &Matcher{}
+
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		m.loadDir(root, "", "")
+		return m
+	}
+
+	repoRoot := findRepoRoot(abs)
+	repoPrefix := ""
+	if repoRoot != "" {
+		if rel, err := filepath.Rel(repoRoot, abs); err == nil && rel != "." {
+			repoPrefix = filepath.ToSlash(rel) + "/"
+		}
+	}
+
+	if path := globalExcludesFile(); path != "" {
+		m.loadFile(path, "", repoPrefix)
+	}
+
+	if repoRoot != "" {
+		m.loadFile(filepath.Join(repoRoot, ".git", "info", "exclude"), "", repoPrefix)
+
+		if rel, err := filepath.Rel(repoRoot, abs); err == nil && rel != "." {
+			parts := strings.Split(rel, string(filepath.Separator))
+			dir := repoRoot
+			for i := range parts {
+				m.loadDir(dir, "", strings.Join(parts[i:], "/")+"/")
+				dir = filepath.Join(dir, parts[i])
+			}
+		}
+	}
+
+	m.loadDir(abs, "", "")
+	return m
+}
+
+func (m *Matcher) Enter(dirPath, relDir string) *Matcher {
+	if m == nil {
+		return nil
+	}
+
+	child := &Matcher{parent: m}
+	child.loadDir(dirPath, filepath.ToSlash(relDir), "")
+	if len(child.sets) == 0 {
+		return m
+	}
+	return child
+}
+
+func (m *Matcher) Ignored(relPath string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+
+	relPath = filepath.ToSlash(relPath)
+	if isDir && (relPath == ".git" || strings.HasSuffix(relPath, "/.git")) {
+		return true
+	}
+
+	for level := m; level != nil; level = level.parent {
+		for i := len(level.sets) - 1; i >= 0; i-- {
+			set := level.sets[i]
+
+			path := relPath
+			if set.base != "" {
+				if !strings.HasPrefix(path, set.base+"/") {
+					continue
+				}
+				path = path[len(set.base)+1:]
+			}
+			path = set.prefix + path
+
+			for j := len(set.rules) - 1; j >= 0; j-- {
+				r := set.rules[j]
+				if r.dirOnly && !isDir {
+					continue
+				}
+				if r.re.MatchString(path) {
+					return !r.negate
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (m *Matcher) loadDir(dir, base, prefix string) {
+	for _, name := range FileNames {
+		m.loadFile(filepath.Join(dir, name), base, prefix)
+	}
+}
+
+func (m *Matcher) loadFile(path, base, prefix string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	set := ruleSet{base: base, prefix: prefix}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if r, ok := parseRule(scanner.Text()); ok {
+			set.rules = append(set.rules, r)
+		}
+	}
+
+	if len(set.rules) > 0 {
+		m.sets = append(m.sets, set)
+	}
+}
+
+func parseRule(line string) (rule, bool) {
+	line = strings.TrimRight(line, "\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+
+	var r rule
+	switch {
+	case strings.HasPrefix(line, "!"):
+		r.negate = true
+		line = line[1:]
+	case strings.HasPrefix(line, "\\!"), strings.HasPrefix(line, "\\#"):
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	if line == "" {
+		return rule{}, false
+	}
+
+	expr, err := filter.GlobToRegexp(line)
+	if err != nil {
+		return rule{}, false
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return rule{}, false
+	}
+	r.re = re
+	return r, true
+}
+
+func findRepoRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func globalExcludesFile() string {
+	home, _ := os.UserHomeDir()
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" && home != "" {
+		configHome = filepath.Join(home, ".config")
+	}
+
+	for _, config := range []string{filepath.Join(home, ".gitconfig"), filepath.Join(configHome, "git", "config")} {
+		if path := readExcludesFile(config); path != "" {
+			if strings.HasPrefix(path, "~/") && home != "" {
+				path = filepath.Join(home, path[2:])
+			}
+			return path
+		}
+	}
+
+	if configHome == "" {
+		return ""
+	}
+	return filepath.Join(configHome, "git", "ignore")
+}
+
+func readExcludesFile(configPath string) string {
+	f, err := os.Open(configPath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inCore := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inCore = strings.EqualFold(strings.Trim(line, "[] \t"), "core")
+			continue
+		}
+		if !inCore {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "excludesfile") {
+			return strings.Trim(strings.TrimSpace(value), "\"")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func matcher(t *testing.T, lines ...string) *Matcher {
+	t.Helper()
+	var set ruleSet
+	for _, line := range lines {
+		if r, ok := parseRule(line); ok {
+			set.rules = append(set.rules, r)
+		}
+	}
+	return &Matcher{sets: []ruleSet{set}}
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		line    string
+		ok      bool
+		negate  bool
+		dirOnly bool
+	}{
+		{"", false, false, false},
+		{"# comment", false, false, false},
+		{"\\#not-a-comment", true, false, false},
+		{"*.log", true, false, false},
+		{"*.log   ", true, false, false},
+		{"!keep.log", true, true, false},
+		{"\\!important", true, false, false},
+		{"build/", true, false, true},
+		{"!dist/", true, true, true},
+		{"/", false, false, false},
+		{"[abc", false, false, false},
+	}
+
+	for _, tt := range tests {
+		r, ok := parseRule(tt.line)
+		if ok != tt.ok {
+			t.Errorf("parseRule(%q) ok = %v, want %v", tt.line, ok, tt.ok)
+			continue
+		}
+		if ok && (r.negate != tt.negate || r.dirOnly != tt.dirOnly) {
+			t.Errorf("parseRule(%q) = negate %v, dirOnly %v, want %v, %v",
+				tt.line, r.negate, r.dirOnly, tt.negate, tt.dirOnly)
+		}
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"unanchored at root", []string{"*.log"}, "app.log", false, true},
+		{"unanchored nested", []string{"*.log"}, "a/b/app.log", false, true},
+		{"no match", []string{"*.log"}, "app.txt", false, false},
+		{"negation", []string{"*.log", "!keep.log"}, "keep.log", false, false},
+		{"negation leaves others", []string{"*.log", "!keep.log"}, "drop.log", false, true},
+		{"last rule wins", []string{"!keep.log", "*.log"}, "keep.log", false, true},
+		{"leading slash anchors", []string{"/root.txt"}, "root.txt", false, true},
+		{"leading slash skips nested", []string{"/root.txt"}, "sub/root.txt", false, false},
+		{"inner slash anchors", []string{"doc/*.md"}, "doc/a.md", false, true},
+		{"inner slash skips nested", []string{"doc/*.md"}, "x/doc/a.md", false, false},
+		{"star stays in segment", []string{"doc/*.md"}, "doc/sub/a.md", false, false},
+		{"dir only matches dir", []string{"build/"}, "build", true, true},
+		{"dir only skips file", []string{"build/"}, "build", false, false},
+		{"dir only nested", []string{"build/"}, "src/build", true, true},
+		{"double star prefix", []string{"**/cache"}, "a/b/cache", true, true},
+		{"double star suffix", []string{"logs/**"}, "logs/2024/app.log", false, true},
+		{"double star middle", []string{"a/**/z"}, "a/z", false, true},
+		{"double star middle deep", []string{"a/**/z"}, "a/b/c/z", false, true},
+		{"escaped bang", []string{"\\!important"}, "!important", false, true},
+		{"git dir", nil, "sub/.git", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matcher(t, tt.rules...).Ignored(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("Ignored(%q, %v) with %q = %v, want %v", tt.path, tt.isDir, tt.rules, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnterNested(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(root, ".gitignore"), "*.tmp\n/only-root\n")
+	write(filepath.Join(sub, ".ignore"), "!keep.tmp\nlocal/\n")
+
+	m := New(root).Enter(sub, "sub")
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"x.tmp", false, true},
+		{"sub/x.tmp", false, true},
+		{"sub/keep.tmp", false, false},
+		{"keep.tmp", false, true},
+		{"only-root", false, true},
+		{"sub/only-root", false, false},
+		{"sub/local", true, true},
+		{"local", true, false},
+	}
+	for _, tt := range tests {
+		if got := m.Ignored(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Ignored(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rinimisini112/gls/finder"
+	"github.com/rinimisini112/gls/watch"
+)
+
+func showIndexHelp() {
+	fmt.Println("\n📇 Usage: gls index build|update|watch [options] [directory]")
+	fmt.Println("  build        Index every directory below the root")
+	fmt.Println("  update       Re-read only directories that changed since the last build")
+	fmt.Println("  watch        Keep the index up to date as files change, until interrupted")
+	fmt.Println("Options:")
+	fmt.Println("  --no-ignore  Also index paths excluded by .gitignore, .ignore and .glsignore")
+	fmt.Println("  --timeout=DURATION  Stop indexing after DURATION")
+}
+
+func runIndex(args []string) {
+	if len(args) == 0 || (args[0] != "build" && args[0] != "update" && args[0] != "watch") {
+		showIndexHelp()
+		return
+	}
+
+	command := args[0]
+	dir := "."
+	opts := finder.Options{}
+	var timeout time.Duration
+
+	for _, arg := range args[1:] {
+		switch {
+		case arg == "--no-ignore":
+			opts.NoIgnore = true
+		case strings.HasPrefix(arg, "--timeout="):
+			d, err := time.ParseDuration(strings.TrimPrefix(arg, "--timeout="))
+			if err != nil {
+				fmt.Println("❌ Error:", err)
+				return
+			}
+			timeout = d
+		case strings.HasPrefix(arg, "-"):
+			fmt.Println("❌ Invalid option:", arg)
+			showIndexHelp()
+			return
+		default:
+			dir = arg
+		}
+	}
+
+	if command == "watch" {
+		watchIndex(dir, opts)
+		return
+	}
+
+	var prev *finder.Index
+	if command == "update" {
+		var err error
+		if prev, err = finder.LoadIndex(dir); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				fmt.Printf("❌ No index for %s, run gls index build first\n", dir)
+			} else {
+				fmt.Println("❌ Error:", err)
+			}
+			return
+		}
+	}
+
+	ctx, cancel := searchContext(timeout)
+	startTime := time.Now()
+	idx, err := finder.BuildIndex(ctx, dir, prev, opts)
+	interrupted := ctx.Err() != nil
+	cancel()
+	reportSearchError(err)
+	if interrupted {
+		fmt.Println("❌ Indexing did not finish, keeping the previous index")
+		return
+	}
+
+	if err := idx.Save(); err != nil {
+		fmt.Println("❌ Error saving index:", err)
+		return
+	}
+
+	entries, reused := 0, 0
+	for path, d := range idx.Dirs {
+		entries += len(d.Entries)
+		if prev != nil && prev.Dirs[path].ModTime.Equal(d.ModTime) {
+			reused++
+		}
+	}
+
+	fmt.Printf("✅ Indexed %d directories, %d entries in %s", len(idx.Dirs), entries, time.Since(startTime))
+	if prev != nil {
+		fmt.Printf(" (%d unchanged)", reused)
+	}
+	fmt.Println()
+}
+
+const indexSaveInterval = 5 * time.Second
+
+func watchIndex(dir string, opts finder.Options) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	prev, _ := finder.LoadIndex(dir)
+	idx, err := finder.BuildIndex(ctx, dir, prev, opts)
+	reportSearchError(err)
+	if ctx.Err() != nil {
+		return
+	}
+
+	w, err := watch.New()
+	if err != nil {
+		fmt.Println("❌ Cannot watch for changes:", err)
+		return
+	}
+	defer w.Close()
+
+	watched := make(map[string]bool)
+	addWatches := func() {
+		for _, path := range idx.Paths() {
+			if watched[path] {
+				continue
+			}
+			if err := w.Add(path); err != nil {
+				fmt.Fprintln(os.Stderr, "⚠️", err)
+			}
+			watched[path] = true
+		}
+	}
+	addWatches()
+
+	fmt.Printf("👀 Watching %d directories under %s, press Ctrl-C to stop\n", len(watched), idx.Root)
+
+	dirty := make(map[string]bool)
+	flush := time.NewTicker(500 * time.Millisecond)
+	defer flush.Stop()
+	lastSave := time.Now()
+	unsaved := indexChanged(prev, idx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := idx.Save(); err != nil {
+				fmt.Println("❌ Error saving index:", err)
+			}
+			return
+
+		case err := <-w.Errors():
+			fmt.Fprintln(os.Stderr, "⚠️", err)
+			if errors.Is(err, watch.ErrOverflow) {
+				dirty[idx.Root] = true
+			}
+
+		case event, ok := <-w.Events():
+			if !ok {
+				return
+			}
+			dirty[filepath.Dir(event.Path)] = true
+			if event.IsDir && event.Op&(watch.Remove|watch.Rename) != 0 {
+				idx.Forget(event.Path)
+				w.Remove(event.Path)
+				delete(watched, event.Path)
+			}
+
+		case <-flush.C:
+			if len(dirty) > 0 {
+				for path := range dirty {
+					reportSearchError(idx.Refresh(ctx, path, opts))
+				}
+				fmt.Printf("🔄 Updated %d directories\n", len(dirty))
+				dirty = make(map[string]bool)
+				addWatches()
+				unsaved = true
+			}
+
+			if unsaved && time.Since(lastSave) >= indexSaveInterval {
+				if err := idx.Save(); err != nil {
+					fmt.Println("❌ Error saving index:", err)
+				}
+				lastSave = time.Now()
+				unsaved = false
+			}
+		}
+	}
+}
+
+// indexChanged reports whether building idx on top of prev found any
+// directory added, removed or modified since prev was saved.
+func indexChanged(prev, idx *finder.Index) bool {
+	if prev == nil || len(prev.Dirs) != len(idx.Dirs) {
+		return true
+	}
+	for path, dir := range idx.Dirs {
+		old, ok := prev.Dirs[path]
+		if !ok || !old.ModTime.Equal(dir.ModTime) || len(old.Entries) != len(dir.Entries) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,60 +1,106 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/olekukonko/tablewriter"
+	"github.com/rinimisini112/gls/filter"
 	"github.com/rinimisini112/gls/finder"
 	"github.com/rinimisini112/gls/operations"
+	"github.com/rinimisini112/gls/output"
+	"github.com/rinimisini112/gls/query"
 	"github.com/rinimisini112/gls/structures"
 	"github.com/rinimisini112/gls/tui"
 )
 
-func printTable(files []structures.FileInfo, showHidden bool, withGroupAndUser bool, fullDirSize bool) {
+type displayOptions struct {
+	showHidden       bool
+	withGroupAndUser bool
+	fullDirSize      bool
+	sizes            *finder.SizeScanner
+	timeField        string
+	timeStyle        string
+	classify         bool
+	extraColumns     []output.Column
+	changes          map[string]change
+}
+
+func printTable(files []structures.FileInfo, opts displayOptions) {
+	writeTable(os.Stdout, files, opts)
+}
+
+func writeTable(w io.Writer, files []structures.FileInfo, opts displayOptions) {
 	if len(files) == 0 {
-		fmt.Println("")
-		fmt.Println("All who wander are not lost, But what you are looking for is nowhere to be found")
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "All who wander are not lost, But what you are looking for is nowhere to be found")
 		return
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := tablewriter.NewWriter(w)
 	headers := []string{"Type", "Name"}
-	if withGroupAndUser {
+	if opts.withGroupAndUser {
 		headers = append(headers, "User:Group")
 	}
-	headers = append(headers, "Permissions", "Size", "Last Modified")
+	headers = append(headers, "Permissions", "Size", output.TimeHeader(opts.timeField))
+	for _, column := range opts.extraColumns {
+		headers = append(headers, column.Header)
+	}
 
 	table.SetHeader(headers)
 	table.SetRowLine(true)
 	table.SetAutoWrapText(false)
 
+	dirSizes := make(map[string]int64)
+	if opts.fullDirSize {
+		var dirs []string
+		for _, file := range files {
+			if file.IsDir && (opts.showHidden || !file.Hidden) {
+				dirs = append(dirs, file.Path)
+			}
+		}
+
+		sizes, err := opts.sizes.DirSizes(dirs)
+		for i, dir := range dirs {
+			dirSizes[dir] = sizes[i]
+		}
+		defer reportSearchError(err)
+	}
+
 	for _, file := range files {
-		if !showHidden && file.Hidden {
+		if !opts.showHidden && file.Hidden {
 			continue
 		}
 
 		row := []string{
+			output.TypeLabel(file),
 			func() string {
-				if file.IsDir {
-					return "📂 Dir"
+				if len(file.MatchPositions) > 0 {
+					return output.Highlight(file.Path, file.MatchPositions)
 				}
-				return "📄 File"
+				if c, ok := opts.changes[file.Path]; ok {
+					return c.paint(output.DisplayName(file, opts.classify))
+				}
+				return output.DisplayName(file, opts.classify)
 			}(),
-			file.Name,
 		}
 
-		if withGroupAndUser {
+		if opts.withGroupAndUser {
 			row = append(row, file.UserAndGroup)
 		}
 
 		row = append(row, file.Permissions)
 
 		size := file.RawSize
-		if fullDirSize && file.IsDir {
-			size = finder.CalculateDirSize(file.Path)
+		if opts.fullDirSize && file.IsDir {
+			size = dirSizes[file.Path]
 		}
 
 		colorSize := func(size int64) string {
@@ -76,7 +122,10 @@ func printTable(files []structures.FileInfo, showHidden bool, withGroupAndUser b
 		}
 
 		row = append(row, colorSize(size))
-		row = append(row, file.ModTime)
+		row = append(row, output.FormatTime(file.Time(opts.timeField), opts.timeStyle))
+		for _, column := range opts.extraColumns {
+			row = append(row, column.Value(file))
+		}
 
 		table.Append(row)
 	}
@@ -84,25 +133,264 @@ func printTable(files []structures.FileInfo, showHidden bool, withGroupAndUser b
 	table.Render()
 }
 
+func visibleFiles(files []structures.FileInfo, showHidden bool) []structures.FileInfo {
+	if showHidden {
+		return files
+	}
+
+	visible := make([]structures.FileInfo, 0, len(files))
+	for _, file := range files {
+		if !file.Hidden {
+			visible = append(visible, file)
+		}
+	}
+	return visible
+}
+
+func printSections(tree *structures.DirTree, opts displayOptions) {
+	tree.Walk(func(node *structures.DirTree) {
+		if node.Depth > 1 {
+			fmt.Printf("\n📂 %s:\n", node.Path)
+		}
+		if node.Err != nil {
+			fmt.Println("❌ Error:", node.Err)
+			return
+		}
+
+		opts.showHidden = true
+		printTable(node.Files, opts)
+		fmt.Printf("Total: %s\n", output.DirSummary(node))
+	})
+
+	fmt.Printf("\n%s\n", output.TreeSummary(tree))
+}
+
+func writeStream(stream output.StreamWriter, files []structures.FileInfo) {
+	for _, file := range files {
+		if err := stream.Write(file); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return
+		}
+	}
+}
+
+// sizeDirs replaces the inode size of each directory in files with the total
+// of its subtree. It does nothing unless full directory sizes were asked for.
+func sizeDirs(files []structures.FileInfo, sizes *finder.SizeScanner) error {
+	if sizes == nil {
+		return nil
+	}
+
+	var dirs []string
+	var at []int
+	for i, file := range files {
+		if file.IsDir {
+			dirs = append(dirs, file.Path)
+			at = append(at, i)
+		}
+	}
+
+	totals, err := sizes.DirSizes(dirs)
+	for n, i := range at {
+		setDirSize(&files[i], totals[n])
+	}
+	return err
+}
+
+func setDirSize(file *structures.FileInfo, size int64) {
+	file.RawSize = size
+	file.Size = humanize.Bytes(uint64(size))
+}
+
+func streamListing(
+	stream output.StreamWriter,
+	dir, filterType string,
+	showHidden, withOwner, follow, withBirth bool,
+	limit int,
+	sizes *finder.SizeScanner,
+	preds ...filter.Predicate,
+) error {
+	written := 0
+	var errs []error
+
+	err := operations.StreamFiles(dir, withOwner, follow, withBirth, func(file structures.FileInfo) bool {
+		if !showHidden && file.Hidden {
+			return true
+		}
+		if !operations.Matches(file, filterType, preds...) {
+			return true
+		}
+		if file.IsDir && sizes != nil {
+			size, err := sizes.DirSize(file.Path)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			setDirSize(&file, size)
+		}
+		if err := stream.Write(file); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return false
+		}
+		written++
+		return limit <= 0 || written < limit
+	})
+	reportSearchError(errors.Join(errs...))
+	return err
+}
+
+func streamSearch(
+	ctx context.Context,
+	stream output.StreamWriter,
+	query, dir string,
+	opts finder.Options,
+	showHidden bool,
+	limit int,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	written := 0
+
+	err := finder.StreamContext(ctx, query, dir, opts, func(file structures.FileInfo) {
+		if !showHidden && file.Hidden {
+			return
+		}
+		if limit > 0 && written >= limit {
+			return
+		}
+		if err := stream.Write(file); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return
+		}
+		written++
+		if limit > 0 && written >= limit {
+			cancel()
+		}
+	})
+
+	if limit > 0 && written >= limit {
+		return withoutCanceled(err)
+	}
+	return err
+}
+
+// withoutCanceled drops the cancellation from a joined search error, for
+// searches that were stopped on purpose.
+func withoutCanceled(err error) error {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	var kept []error
+	for _, e := range errs {
+		if !errors.Is(e, context.Canceled) {
+			kept = append(kept, e)
+		}
+	}
+	return errors.Join(kept...)
+}
+
+func searchContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+func reportSearchError(err error) {
+	if err == nil {
+		return
+	}
+
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	for _, e := range errs {
+		var dirErr *finder.DirError
+		switch {
+		case errors.Is(e, context.DeadlineExceeded):
+			fmt.Fprintln(os.Stderr, "⏱️ Search timed out, showing partial results")
+		case errors.Is(e, context.Canceled):
+			fmt.Fprintln(os.Stderr, "🛑 Search interrupted, showing partial results")
+		case errors.As(e, &dirErr):
+			fmt.Fprintf(os.Stderr, "⚠️ Skipped %v\n", dirErr)
+		default:
+			fmt.Fprintln(os.Stderr, "⚠️", e)
+		}
+	}
+}
+
 func showHelp() {
 	fmt.Println("\n📂 Usage: gls [options] [directories]")
+	fmt.Println("       gls dupes [options] [directories]  Find duplicate files (see gls dupes --help)")
+	fmt.Println("       gls index build|update [directory]  Maintain a search index for a tree")
+	fmt.Println("       gls snapshot save|diff, gls diff-dirs  Record and compare listings (see gls snapshot --help)")
 	fmt.Println("Options:")
 	fmt.Println("  -s=name 	    Sort by name (default)")
 	fmt.Println("  -s=size    	Sort by size")
 	fmt.Println("  -s=date    	Sort by date")
+	fmt.Println("  -s=none, -U  Do not sort; stream entries in directory order")
+	fmt.Println("  --time=mtime|atime|ctime|birth  Timestamp to show and sort by (default mtime)")
+	fmt.Println("  --time-style=iso|relative|full|+FORMAT  How timestamps are displayed")
 	fmt.Println("  -a         	Show hidden files")
 	fmt.Println("  -p         	Preview files")
 	fmt.Println("  -t=dir     	Show only directories")
 	fmt.Println("  -t=file    	Show only files")
 	fmt.Println("  -t=hidden  	Show only hidden files")
+	fmt.Println("  -t=link    	Show only symbolic links")
+	fmt.Println("  -t=broken  	Show only broken symbolic links")
+	fmt.Println("  -t=exec|socket|fifo|block|char|device  Show only executables, sockets, FIFOs or devices")
+	fmt.Println("  -F           Append a type indicator (one of / * @ | =) to names")
+	fmt.Println("  -L, --follow  Follow symbolic links when listing, recursing and searching")
 	fmt.Println("  -l [limit] 	Limit the number of files displayed")
 	fmt.Println("  -s [query] 	Search for files containing 'query'")
 	fmt.Println("  --help, -h   Show this help message")
 	fmt.Println("  --rename <old> <new>     Rename a file")
-	fmt.Println("  -i           Interactive mode")
+	fmt.Println("  --watch      Redraw the listing in place as the directories change, highlighting what changed")
+	fmt.Println("  -i           Interactive mode (press u for the disk-usage explorer)")
+	fmt.Println("  --usage      Interactive disk-usage explorer, sorted by size (l/h to navigate, d to delete)")
 	fmt.Println("  -sa          Search for files with user and group")
+	fmt.Println("  --no-index   Search the filesystem even when a gls index covers the directory")
+	fmt.Println("  --no-cache   Do not reuse or remember search results")
+	fmt.Println("  --cache-stats  Print search cache hits, misses and evictions after searching")
+	fmt.Println("  --fuzzy      Fuzzy-match the search query against paths and rank results")
+	fmt.Println("  --grep=PATTERN  Search file contents for the regular expression PATTERN")
+	fmt.Println("  --grep-literal  Treat the --grep pattern as a literal string")
+	fmt.Println("  --grep-ignore-case  Match the --grep pattern case-insensitively")
+	fmt.Println("  --grep-max-size=SIZE  Skip files larger than SIZE when grepping (default 10M)")
+	fmt.Println("  --timeout=DURATION  Stop searching after DURATION (e.g. 5s) and show partial results")
+	fmt.Println("  -q [expr]    Filter with a query, e.g. 'size > 10M and ext = go'")
+	fmt.Println("               fields: name path ext owner group size mtime atime ctime birth depth perms type hidden")
+	fmt.Println("               operators: = != < <= > >=, ~ (glob), !~, =~ (regex); combine with and/or/not and ( )")
 	fmt.Println("  --version, -v  Show version")
 	fmt.Println("  -fullDirSize  Show full directory size")
+	fmt.Println("  --du         Directory sizes from allocated blocks, like du (implies -fullDirSize)")
+	fmt.Println("  --apparent-size  Directory sizes from file lengths (default, implies -fullDirSize)")
+	fmt.Println("  -x           Do not cross filesystem boundaries when sizing directories")
+	fmt.Println("  --format=table|json|ndjson|csv|tsv  Output format (default table)")
+	fmt.Println("  --columns=name,size,...  Columns for csv/tsv (name,type,size,mtime,atime,ctime,birth,perms,owner,target,path,inode,links,blocks,dev,rdev)")
+	fmt.Println("               inode, links, blocks, dev and rdev are also added to the table view")
+	fmt.Println("  -R           List subdirectories recursively")
+	fmt.Println("  --tree       Recursive listing rendered as a tree")
+	fmt.Println("  --depth=N    Limit recursion to N levels (implies -R)")
+	fmt.Println("  --no-ignore  Do not honor .gitignore, .ignore and .glsignore when searching or recursing")
+	fmt.Println("  --include=GLOB  Only show paths matching GLOB (repeatable, supports **)")
+	fmt.Println("  --exclude=GLOB  Skip paths matching GLOB (repeatable, supports **)")
+	fmt.Println("  --regex=PATTERN  Only show paths matching the regular expression (repeatable)")
+	fmt.Println("  --size=[+|-]N[k|M|G]  Size greater than (+), less than (-) or equal to N")
+	fmt.Println("  --newer=AGE|DATE  Modified after e.g. 2d, 12h or 2025-01-01 (uses --time)")
+	fmt.Println("  --older=AGE|DATE  Modified before e.g. 90d or 2025-01-01 (uses --time)")
+	fmt.Println("  --owner=NAME  Only files owned by user NAME")
+	fmt.Println("  --group=NAME  Only files owned by group NAME")
+	fmt.Println("  --perm=[/|-]MODE  Permissions exactly MODE, any of /MODE, all of -MODE (e.g. /o+w, 644)")
 }
 
 func main() {
@@ -110,32 +398,111 @@ func main() {
 	sortBy := "name"
 	showHidden := false
 	searchQuery := ""
+	searching := false
 	filterType := ""
 	limit := -1
 	interactive := false
 	withGroupAndUser := false
 	fullDirSize := false
+	format := output.FormatTable
+	columnSpec := ""
+	recursive := false
+	treeView := false
+	maxDepth := 0
+	timeField := "mtime"
+	timeStyle := ""
+	pathFilter := &filter.Set{}
+	criteria := &filter.Criteria{}
+	noIgnore := false
+	fuzzy := false
+	useIndex := true
+	noCache := false
+	cacheStats := false
+	watching := false
+	follow := false
+	classify := false
+	sizeOpts := finder.SizeOptions{}
+	usageView := false
+	grepPattern := ""
+	grepLiteral := false
+	grepIgnoreCase := false
+	grepMaxSize := int64(finder.DefaultGrepMaxSize)
+	var timeout time.Duration
 
 	args := os.Args[1:]
+	if len(args) > 0 {
+		switch args[0] {
+		case "dupes":
+			runDupes(args[1:])
+			return
+		case "index":
+			runIndex(args[1:])
+			return
+		case "snapshot":
+			runSnapshot(args[1:])
+			return
+		case "diff-dirs":
+			runDiffDirs(args[1:])
+			return
+		}
+	}
+
 	validArgs := map[string]bool{
 		"--help": true, "-h": true,
 		"--rename": true,
-		"-s=name":  true, "-s=size": true, "-s=date": true,
+		"-s=name":  true, "-s=size": true, "-s=date": true, "-s=none": true,
+		"-U": true,
 		"-a": true, "-p": true,
 		"-t=dir": true, "-t=file": true, "-t=hidden": true,
-		"-l": true, "-s": true,
-		"-i":            true,
-		"-sa":           true,
-		"--interactive": true,
-		"--version":     true,
-		"-v":            true,
-		"-fullDirSize":  true,
+		"-l": true, "-s": true, "-q": true,
+		"-i":                 true,
+		"-sa":                true,
+		"--interactive":      true,
+		"--version":          true,
+		"-v":                 true,
+		"-fullDirSize":       true,
+		"-R":                 true,
+		"--tree":             true,
+		"--no-ignore":        true,
+		"--fuzzy":            true,
+		"--follow":           true,
+		"--no-index":         true,
+		"--no-cache":         true,
+		"--cache-stats":      true,
+		"--watch":            true,
+		"-L":                 true,
+		"-F":                 true,
+		"--du":               true,
+		"--usage":            true,
+		"--apparent-size":    true,
+		"-x":                 true,
+		"-t=link":            true,
+		"-t=broken":          true,
+		"--grep-literal":     true,
+		"--grep-ignore-case": true,
+	}
+	validPrefixes := []string{"-t=", "--format=", "--columns=", "--depth=", "--time=", "--time-style=",
+		"--include=", "--exclude=", "--regex=",
+		"--timeout=", "--grep=", "--grep-max-size=",
+		"--size=", "--newer=", "--older=", "--owner=", "--group=", "--perm=",
+	}
+
+	isValidArg := func(arg string) bool {
+		if validArgs[arg] || !strings.HasPrefix(arg, "-") {
+			return true
+		}
+		for _, prefix := range validPrefixes {
+			if strings.HasPrefix(arg, prefix) {
+				return true
+			}
+		}
+		return false
 	}
 
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 
-		if _, exists := validArgs[arg]; !exists && !strings.HasPrefix(arg, "-t=") {
+		if !isValidArg(arg) {
 			fmt.Println("❌ Invalid option:", arg)
 			showHelp()
 			return
@@ -171,6 +538,10 @@ func main() {
 			fmt.Printf("✅ Successfully renamed %q to %q\n", oldName, newName)
 			i += consumed
 			return
+		case arg == "-s=name":
+			sortBy = "name"
+		case arg == "-s=none" || arg == "-U":
+			sortBy = "none"
 		case arg == "-s=size":
 			sortBy = "size"
 		case arg == "-s=date":
@@ -181,6 +552,112 @@ func main() {
 			fullDirSize = true
 		case strings.HasPrefix(arg, "-t="):
 			filterType = strings.Split(arg, "=")[1]
+		case strings.HasPrefix(arg, "--format="):
+			f, err := output.ParseFormat(strings.TrimPrefix(arg, "--format="))
+			if err != nil {
+				fmt.Println("❌ Error:", err)
+				return
+			}
+			format = f
+		case strings.HasPrefix(arg, "--columns="):
+			columnSpec = strings.TrimPrefix(arg, "--columns=")
+		case strings.HasPrefix(arg, "--time="):
+			timeField = strings.TrimPrefix(arg, "--time=")
+			if err := output.ParseTimeField(timeField); err != nil {
+				fmt.Println("❌ Error:", err)
+				return
+			}
+		case strings.HasPrefix(arg, "--time-style="):
+			timeStyle = strings.TrimPrefix(arg, "--time-style=")
+			if err := output.ParseTimeStyle(timeStyle); err != nil {
+				fmt.Println("❌ Error:", err)
+				return
+			}
+		case strings.HasPrefix(arg, "--include="):
+			if err := pathFilter.AddInclude(strings.TrimPrefix(arg, "--include=")); err != nil {
+				fmt.Println("❌ Error:", err)
+				return
+			}
+		case strings.HasPrefix(arg, "--exclude="):
+			if err := pathFilter.AddExclude(strings.TrimPrefix(arg, "--exclude=")); err != nil {
+				fmt.Println("❌ Error:", err)
+				return
+			}
+		case strings.HasPrefix(arg, "--regex="):
+			if err := pathFilter.AddRegex(strings.TrimPrefix(arg, "--regex=")); err != nil {
+				fmt.Println("❌ Error:", err)
+				return
+			}
+		case strings.HasPrefix(arg, "--size="),
+			strings.HasPrefix(arg, "--newer="),
+			strings.HasPrefix(arg, "--older="),
+			strings.HasPrefix(arg, "--owner="),
+			strings.HasPrefix(arg, "--group="),
+			strings.HasPrefix(arg, "--perm="):
+			name, spec, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+			if err := criteria.Add(name, spec); err != nil {
+				fmt.Println("❌ Error:", err)
+				return
+			}
+		case strings.HasPrefix(arg, "--timeout="):
+			d, err := time.ParseDuration(strings.TrimPrefix(arg, "--timeout="))
+			if err != nil || d <= 0 {
+				fmt.Println("❌ Error: --timeout requires a duration such as 5s or 1m")
+				return
+			}
+			timeout = d
+		case strings.HasPrefix(arg, "--grep="):
+			grepPattern = strings.TrimPrefix(arg, "--grep=")
+			searching = true
+		case arg == "--grep-literal":
+			grepLiteral = true
+		case arg == "--grep-ignore-case":
+			grepIgnoreCase = true
+		case strings.HasPrefix(arg, "--grep-max-size="):
+			size, err := filter.ParseSize(strings.TrimPrefix(arg, "--grep-max-size="))
+			if err != nil {
+				fmt.Println("❌ Error:", err)
+				return
+			}
+			grepMaxSize = size
+		case arg == "--usage":
+			interactive = true
+			usageView = true
+		case arg == "--du":
+			sizeOpts.DiskUsage = true
+			fullDirSize = true
+		case arg == "--apparent-size":
+			sizeOpts.DiskUsage = false
+			fullDirSize = true
+		case arg == "-x":
+			sizeOpts.OneFileSystem = true
+		case arg == "-F":
+			classify = true
+		case arg == "--follow" || arg == "-L":
+			follow = true
+		case arg == "--no-index":
+			useIndex = false
+		case arg == "--no-cache":
+			noCache = true
+		case arg == "--cache-stats":
+			cacheStats = true
+		case arg == "--watch":
+			watching = true
+		case arg == "--fuzzy":
+			fuzzy = true
+		case arg == "--no-ignore":
+			noIgnore = true
+		case arg == "-R":
+			recursive = true
+		case arg == "--tree":
+			recursive = true
+			treeView = true
+		case strings.HasPrefix(arg, "--depth="):
+			if _, err := fmt.Sscanf(strings.TrimPrefix(arg, "--depth="), "%d", &maxDepth); err != nil || maxDepth < 1 {
+				fmt.Println("❌ Error: --depth requires a positive number")
+				return
+			}
+			recursive = true
 		case arg == "-l":
 			if i+1 < len(args) {
 				fmt.Sscanf(args[i+1], "%d", &limit)
@@ -189,11 +666,31 @@ func main() {
 		case arg == "-s":
 			if i+1 < len(args) {
 				searchQuery = args[i+1]
+				searching = true
 				i++
 			}
+		case arg == "-q":
+			if i+1 >= len(args) {
+				fmt.Println("❌ Error: -q requires a query expression")
+				return
+			}
+			q, err := query.Parse(args[i+1])
+			if err != nil {
+				fmt.Println("❌ Query error:", err)
+				return
+			}
+			criteria.AddPredicate("query="+q.String(), q.Match)
+			if q.NeedsOwner() {
+				criteria.RequireOwner()
+			}
+			if q.NeedsBirth() {
+				criteria.RequireBirth()
+			}
+			i++
 		case arg == "-sa":
 			if i+1 < len(args) {
 				searchQuery = args[i+1]
+				searching = true
 				withGroupAndUser = true
 				i++
 			}
@@ -209,7 +706,7 @@ func main() {
 		if len(dirs) == 0 {
 			dirs = append(dirs, ".")
 		}
-		tui.StartInteractiveMode(dirs[0])
+		tui.StartInteractiveMode(dirs[0], usageView, sizeOpts)
 		return
 	}
 
@@ -217,24 +714,215 @@ func main() {
 		dirs = append(dirs, ".")
 	}
 
-	for _, dir := range dirs {
-		fmt.Printf("\n📂 Listing: %s\n", dir)
+	criteria.TimeField = timeField
 
-		files, err := operations.ListFiles(dir, sortBy)
+	if sortBy == "date" && timeField != "mtime" {
+		sortBy = timeField
+	}
+
+	display := displayOptions{
+		showHidden:       showHidden,
+		withGroupAndUser: withGroupAndUser,
+		fullDirSize:      fullDirSize,
+		timeField:        timeField,
+		timeStyle:        timeStyle,
+		classify:         classify,
+	}
+
+	columns, err := output.ParseColumns(columnSpec)
+	if err != nil {
+		fmt.Println("❌ Error:", err)
+		return
+	}
+	display.extraColumns = output.TableColumns(columns)
+
+	if fullDirSize {
+		display.sizes = finder.NewSizeScanner(sizeOpts)
+		if sizeCache, err := finder.SizeCachePath(); err == nil {
+			if err := display.sizes.Load(sizeCache); err != nil {
+				fmt.Fprintln(os.Stderr, "⚠️ Ignoring size cache:", err)
+			}
+			defer func() {
+				if err := display.sizes.Save(sizeCache); err != nil {
+					fmt.Fprintln(os.Stderr, "⚠️ Could not save size cache:", err)
+				}
+			}()
+		}
+	}
+
+	var grep *finder.Grep
+	if grepPattern != "" {
+		grep, err = finder.NewGrep(grepPattern, grepLiteral, grepIgnoreCase, grepMaxSize)
 		if err != nil {
-			fmt.Println("Error:", err)
+			fmt.Println("❌ Error:", err)
+			return
+		}
+	}
+
+	withOwner := withGroupAndUser ||
+		criteria.NeedsOwner() ||
+		format == output.FormatJSON ||
+		format == output.FormatNDJSON ||
+		(format.IsDelimited() && output.HasColumn(columns, "owner"))
+
+	withBirth := timeField == "birth" ||
+		sortBy == "birth" ||
+		criteria.NeedsBirth() ||
+		format == output.FormatJSON ||
+		format == output.FormatNDJSON ||
+		output.HasColumn(columns, "birth")
+
+	if watching {
+		if searching || recursive || format.IsMachine() {
+			fmt.Println("❌ Error: --watch only works with plain table listings")
+			return
+		}
+		runWatch(dirs, display, func(dir string) ([]structures.FileInfo, error) {
+			files, err := operations.ListFiles(dir, sortBy, withOwner, follow, withBirth)
+			if err != nil {
+				return nil, err
+			}
+			files = operations.FilterFiles(files, filterType, pathFilter.ForRoot(dir), criteria.Match)
+			files = operations.Paginate(files, limit)
+			// Errors show up again when the table is drawn.
+			sizeDirs(files, display.sizes)
+			return files, nil
+		})
+		return
+	}
+
+	var stream output.StreamWriter
+	if format.IsMachine() {
+		stream = output.NewStreamWriter(format, os.Stdout, columns)
+	}
+
+	for _, dir := range dirs {
+		if !format.IsMachine() {
+			fmt.Printf("\n📂 Listing: %s\n", dir)
+		}
+
+		if recursive && !searching {
+			tree, err := operations.ListRecursive(dir, operations.RecursiveOptions{
+				SortBy:     sortBy,
+				FilterType: filterType,
+				ShowHidden: showHidden,
+				WithOwner:  withOwner,
+				WithBirth:  withBirth,
+				MaxDepth:   maxDepth,
+				Filter:     pathFilter,
+				Criteria:   criteria,
+				NoIgnore:   noIgnore,
+				Follow:     follow,
+			})
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				continue
+			}
+
+			switch {
+			case stream != nil:
+				files := operations.Paginate(tree.Flatten(), limit)
+				reportSearchError(sizeDirs(files, display.sizes))
+				writeStream(stream, files)
+			case treeView:
+				output.WriteTree(os.Stdout, tree, classify)
+			default:
+				printSections(tree, display)
+			}
+			continue
+		}
+
+		searchOpts := finder.Options{
+			FilterType:       filterType,
+			WithUserAndGroup: withOwner,
+			WithBirthTime:    withBirth,
+			FullDirSize:      fullDirSize,
+			Size:             sizeOpts,
+			Sizes:            display.sizes,
+			Filter:           pathFilter,
+			Criteria:         criteria,
+			NoIgnore:         noIgnore,
+			Fuzzy:            fuzzy,
+			Follow:           follow,
+			Grep:             grep,
+			NoCache:          noCache,
+		}
+
+		if searching && useIndex {
+			if idx, err := finder.FindIndex(dir); err == nil {
+				searchOpts.Index = idx
+				if !format.IsMachine() {
+					fmt.Printf("📇 Using index of %s from %s\n", idx.Root, humanize.Time(idx.Built))
+				}
+			}
+		}
+
+		if searching && stream != nil {
+			ctx, cancel := searchContext(timeout)
+			reportSearchError(streamSearch(ctx, stream, searchQuery, dir, searchOpts, showHidden, limit))
+			cancel()
 			continue
 		}
 
-		files = operations.FilterFiles(files, filterType)
+		if sortBy == "none" && !searching {
+			out := stream
+			if out == nil {
+				out = output.NewLineWriter(os.Stdout, timeField, timeStyle, withGroupAndUser, classify, display.extraColumns)
+			}
+			if err := streamListing(out, dir, filterType, showHidden, withOwner, follow, withBirth, limit, display.sizes, pathFilter.ForRoot(dir), criteria.Match); err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+			}
+			continue
+		}
 
-		if searchQuery != "" {
-			fmt.Println("🔍 Searching for:", searchQuery)
-			files = finder.Search(searchQuery, dir, filterType, withGroupAndUser, fullDirSize)
+		var files []structures.FileInfo
+		if searching {
+			if grep != nil {
+				fmt.Println("🔍 Searching contents for:", grep.Pattern)
+			} else {
+				fmt.Println("🔍 Searching for:", searchQuery)
+			}
+			ctx, cancel := searchContext(timeout)
+			startTime := time.Now()
+			files, err = finder.SearchContext(ctx, searchQuery, dir, searchOpts)
+			cancel()
+			fmt.Printf("🔍 Search took %s\n", time.Since(startTime))
+			reportSearchError(err)
+		} else {
+			files, err = operations.ListFiles(dir, sortBy, withOwner, follow, withBirth)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				continue
+			}
+			files = operations.FilterFiles(files, filterType, pathFilter.ForRoot(dir), criteria.Match)
+		}
+
+		if stream != nil {
+			files = operations.Paginate(visibleFiles(files, showHidden), limit)
+			if !searching {
+				reportSearchError(sizeDirs(files, display.sizes))
+			}
+			writeStream(stream, files)
+			continue
 		}
 
 		files = operations.Paginate(files, limit)
+		if grep != nil {
+			output.WriteLineMatches(os.Stdout, visibleFiles(files, showHidden))
+			continue
+		}
+		printTable(files, display)
+	}
+
+	if stream != nil {
+		if err := stream.Flush(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+	}
 
-		printTable(files, showHidden, withGroupAndUser, fullDirSize)
+	if searching && cacheStats {
+		stats := finder.SearchCacheStats()
+		fmt.Fprintf(os.Stderr, "🗃️ Search cache: %d hits, %d misses, %d expired, %d stale, %d entries\n",
+			stats.Hits, stats.Misses, stats.Expired, stats.Stale, stats.Entries)
 	}
 }
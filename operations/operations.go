@@ -6,15 +6,20 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/user"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/rinimisini112/gls/filter"
+	"github.com/rinimisini112/gls/finder"
 	"github.com/rinimisini112/gls/structures"
+	"github.com/rinimisini112/gls/sysinfo"
 )
 
 func Rename(oldName, newName string) error {
@@ -98,31 +103,36 @@ func PreviewFile(path string, lines int) string {
 	return content
 }
 
-func FilterFiles(files []structures.FileInfo, filterType string) []structures.FileInfo {
-	if filterType == "" {
+func MatchesType(file structures.FileInfo, filterType string) bool {
+	return file.IsType(filterType)
+}
+
+func FilterFiles(files []structures.FileInfo, filterType string, preds ...filter.Predicate) []structures.FileInfo {
+	if filterType == "" && len(preds) == 0 {
 		return files
 	}
 
 	var filtered []structures.FileInfo
 	for _, file := range files {
-		switch filterType {
-		case "dir":
-			if file.IsDir {
-				filtered = append(filtered, file)
-			}
-		case "file":
-			if !file.IsDir {
-				filtered = append(filtered, file)
-			}
-		case "hidden":
-			if file.Hidden {
-				filtered = append(filtered, file)
-			}
+		if Matches(file, filterType, preds...) {
+			filtered = append(filtered, file)
 		}
 	}
 	return filtered
 }
 
+func Matches(file structures.FileInfo, filterType string, preds ...filter.Predicate) bool {
+	if !MatchesType(file, filterType) {
+		return false
+	}
+	for _, pred := range preds {
+		if !pred(file) {
+			return false
+		}
+	}
+	return true
+}
+
 func Paginate(files []structures.FileInfo, limit int) []structures.FileInfo {
 	if limit > 0 && len(files) > limit {
 		return files[:limit]
@@ -130,78 +140,153 @@ func Paginate(files []structures.FileInfo, limit int) []structures.FileInfo {
 	return files
 }
 
-func ListFiles(dir string, sortBy string) ([]structures.FileInfo, error) {
-	files, err := os.ReadDir(dir)
+const streamBatchSize = 1024
+
+func newFileInfo(dir string, entry os.DirEntry, withOwner, follow, withBirth bool) (structures.FileInfo, error) {
+	info, err := entry.Info()
 	if err != nil {
-		return nil, err
+		return structures.FileInfo{}, err
 	}
 
-	type result struct {
-		file structures.FileInfo
-		err  error
+	path := filepath.Join(dir, entry.Name())
+	isLink := info.Mode()&os.ModeSymlink != 0
+	info, target, broken := finder.ResolveLink(path, info, follow)
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return structures.FileInfo{}, fmt.Errorf("failed to get file stats")
 	}
 
-	ch := make(chan result, len(files))
+	var userAndGroup string
+	if withOwner {
+		userAndGroup = finder.UserAndGroup(stat.Uid, stat.Gid)
+	}
 
-	for _, entry := range files {
-		go func(f os.DirEntry) {
-			info, err := f.Info()
-			if err != nil {
-				ch <- result{err: err}
-				return
-			}
+	atime, ctime := sysinfo.Times(info)
+	inode := sysinfo.InodeOf(info)
 
-			stat, ok := info.Sys().(*syscall.Stat_t)
-			if !ok {
-				ch <- result{err: fmt.Errorf("failed to get file stats")}
-				return
-			}
+	var birth time.Time
+	if withBirth {
+		birth = sysinfo.BirthTime(path, info)
+	}
 
-			usr, err := user.LookupId(fmt.Sprintf("%d", stat.Uid))
-			username := "unknown"
-			if err == nil {
-				username = usr.Username
-			}
+	return structures.FileInfo{
+		Name:         entry.Name(),
+		UserAndGroup: userAndGroup,
+		Permissions:  info.Mode().String(),
+		Mode:         info.Mode(),
+		Inode:        inode.Ino,
+		Links:        inode.Nlink,
+		Blocks:       inode.Blocks,
+		Dev:          inode.Dev,
+		Rdev:         inode.Rdev,
+		Size:         strings.ReplaceAll(humanize.Bytes(uint64(info.Size())), " ", ""),
+		RawSize:      info.Size(),
+		ModTime:      info.ModTime(),
+		AccessTime:   atime,
+		ChangeTime:   ctime,
+		BirthTime:    birth,
+		IsDir:        info.IsDir(),
+		Kind:         structures.KindOf(info.Mode()),
+		IsLink:       isLink,
+		Broken:       broken,
+		LinkTarget:   target,
+		Hidden:       entry.Name()[0] == '.',
+		Path:         path,
+		Depth:        1,
+	}, nil
+}
 
-			grp, err := user.LookupGroupId(fmt.Sprintf("%d", stat.Gid))
-			groupname := "unknown"
-			if err == nil {
-				groupname = grp.Name
-			}
+func statEntries(dir string, entries []os.DirEntry, withOwner, follow, withBirth bool) []structures.FileInfo {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(entries) {
+		workers = len(entries)
+	}
 
-			file := structures.FileInfo{
-				Name:         f.Name(),
-				UserAndGroup: fmt.Sprintf("%s:%s", username, groupname),
-				Permissions:  info.Mode().String(),
-				Size:         strings.ReplaceAll(humanize.Bytes(uint64(info.Size())), " ", ""),
-				RawSize:      info.Size(),
-				ModTime:      info.ModTime().Format(time.RFC822),
-				IsDir:        f.IsDir(),
-				Hidden:       f.Name()[0] == '.',
-				Path:         filepath.Join(dir, f.Name()),
+	files := make([]structures.FileInfo, len(entries))
+	valid := make([]bool, len(entries))
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(entries) {
+					return
+				}
+
+				file, err := newFileInfo(dir, entries[i], withOwner, follow, withBirth)
+				if err != nil {
+					continue
+				}
+				files[i] = file
+				valid[i] = true
 			}
+		}()
+	}
+	wg.Wait()
 
-			ch <- result{file: file}
-		}(entry)
+	fileList := files[:0]
+	for i := range files {
+		if valid[i] {
+			fileList = append(fileList, files[i])
+		}
+	}
+	return fileList
+}
+
+func sizeColor(size int64) string {
+	switch {
+	case size > 1<<30:
+		return "#FF0000"
+	case size > 1<<20:
+		return "#FFA500"
+	default:
+		return "#00FF00"
 	}
+}
+
+func StreamFiles(dir string, withOwner, follow, withBirth bool, emit func(structures.FileInfo) bool) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for {
+		entries, err := f.ReadDir(streamBatchSize)
+		for _, file := range statEntries(dir, entries, withOwner, follow, withBirth) {
+			file.Color = sizeColor(file.RawSize)
+			if !emit(file) {
+				return nil
+			}
+		}
 
-	var fileList []structures.FileInfo
-	for range files {
-		res := <-ch
-		if res.err == nil {
-			fileList = append(fileList, res.file)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
 		}
 	}
+}
+
+// ListFiles stats every entry of dir. Birth times cost an extra syscall per
+// entry, so they are only read with withBirth or when sorting by them.
+func ListFiles(dir string, sortBy string, withOwner, follow, withBirth bool) ([]structures.FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fileList := statEntries(dir, entries, withOwner, follow, withBirth || sortBy == "birth")
 
 	for i := range fileList {
-		switch {
-		case fileList[i].RawSize > 1<<30:
-			fileList[i].Color = "#FF0000"
-		case fileList[i].RawSize > 1<<20:
-			fileList[i].Color = "#FFA500"
-		default:
-			fileList[i].Color = "#00FF00"
-		}
+		fileList[i].Color = sizeColor(fileList[i].RawSize)
 	}
 
 	switch sortBy {
@@ -213,11 +298,13 @@ func ListFiles(dir string, sortBy string) ([]structures.FileInfo, error) {
 		sort.Slice(fileList, func(i, j int) bool {
 			return fileList[i].Name < fileList[j].Name
 		})
-	case "date":
+	case "date", "atime", "ctime", "birth":
+		field := sortBy
+		if field == "date" {
+			field = "mtime"
+		}
 		sort.Slice(fileList, func(i, j int) bool {
-			t1, _ := time.Parse(time.RFC822, fileList[i].ModTime)
-			t2, _ := time.Parse(time.RFC822, fileList[j].ModTime)
-			return t1.After(t2)
+			return fileList[i].Time(field).After(fileList[j].Time(field))
 		})
 	}
 
@@ -0,0 +1,97 @@
+package operations
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+func populateDir(b *testing.B, n int) string {
+	b.Helper()
+
+	dir := b.TempDir()
+	for i := 0; i < n; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("file_%06d.txt", i))
+		if err := os.WriteFile(name, nil, 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// listFilesPerEntry mirrors the previous ListFiles implementation, which
+// spawned one goroutine per entry and did uncached owner lookups.
+func listFilesPerEntry(dir string) ([]structures.FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan structures.FileInfo, len(entries))
+	for _, entry := range entries {
+		go func(f os.DirEntry) {
+			info, err := f.Info()
+			if err != nil {
+				ch <- structures.FileInfo{}
+				return
+			}
+
+			stat := info.Sys().(*syscall.Stat_t)
+			username, groupname := "unknown", "unknown"
+			if usr, err := user.LookupId(fmt.Sprintf("%d", stat.Uid)); err == nil {
+				username = usr.Username
+			}
+			if grp, err := user.LookupGroupId(fmt.Sprintf("%d", stat.Gid)); err == nil {
+				groupname = grp.Name
+			}
+
+			ch <- structures.FileInfo{
+				Name:         f.Name(),
+				UserAndGroup: username + ":" + groupname,
+				RawSize:      info.Size(),
+				ModTime:      info.ModTime(),
+			}
+		}(entry)
+	}
+
+	files := make([]structures.FileInfo, 0, len(entries))
+	for range entries {
+		files = append(files, <-ch)
+	}
+	return files, nil
+}
+
+func BenchmarkListFiles(b *testing.B) {
+	for _, n := range []int{1_000, 20_000} {
+		dir := populateDir(b, n)
+
+		b.Run(fmt.Sprintf("per-entry-goroutines/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := listFilesPerEntry(dir); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("pool/owner/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := ListFiles(dir, "none", true, false, false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("pool/no-owner/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := ListFiles(dir, "none", false, false, false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package operations
+
+import (
+	"os"
+
+	"github.com/rinimisini112/gls/filter"
+	"github.com/rinimisini112/gls/finder"
+	"github.com/rinimisini112/gls/ignore"
+	"github.com/rinimisini112/gls/structures"
+)
+
+type RecursiveOptions struct {
+	SortBy     string
+	FilterType string
+	ShowHidden bool
+	WithOwner  bool
+	WithBirth  bool
+	MaxDepth   int
+	Filter     *filter.Set
+	Criteria   *filter.Criteria
+	NoIgnore   bool
+	Follow     bool
+}
+
+func ListRecursive(dir string, opts RecursiveOptions) (*structures.DirTree, error) {
+	var ignores *ignore.Matcher
+	if !opts.NoIgnore {
+		ignores = ignore.New(dir)
+	}
+	visited := make(map[finder.FileID]bool)
+	markVisited(visited, dir)
+	return listTree(dir, dir, opts, ignores, visited, 1)
+}
+
+func markVisited(visited map[finder.FileID]bool, dir string) bool {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return false
+	}
+	id, ok := finder.FileIDOf(info)
+	if !ok || visited[id] {
+		return false
+	}
+	visited[id] = true
+	return true
+}
+
+func listTree(root, dir string, opts RecursiveOptions, ignores *ignore.Matcher, visited map[finder.FileID]bool, depth int) (*structures.DirTree, error) {
+	node := &structures.DirTree{Path: dir, Depth: depth}
+
+	files, err := ListFiles(dir, opts.SortBy, opts.WithOwner, opts.Follow, opts.WithBirth)
+	if err != nil {
+		return nil, err
+	}
+
+	var visible []structures.FileInfo
+	for _, file := range files {
+		if !opts.ShowHidden && file.Hidden {
+			continue
+		}
+		if ignores.Ignored(filter.RelPath(root, file.Path), file.IsDir) {
+			continue
+		}
+		file.Depth = depth
+		visible = append(visible, file)
+	}
+
+	node.Files = FilterFiles(visible, opts.FilterType, opts.Filter.ForRoot(root), opts.Criteria.Match)
+	for _, file := range node.Files {
+		if file.IsDir {
+			node.DirCount++
+		} else {
+			node.FileCount++
+		}
+		node.TotalSize += file.RawSize
+	}
+
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		return node, nil
+	}
+
+	for _, file := range visible {
+		relPath := filter.RelPath(root, file.Path)
+		if !file.IsDir || opts.Filter.Prune(relPath) {
+			continue
+		}
+		if file.IsLink && !opts.Follow {
+			continue
+		}
+		if !markVisited(visited, file.Path) {
+			continue
+		}
+
+		child, err := listTree(root, file.Path, opts, ignores.Enter(file.Path, relPath), visited, depth+1)
+		if err != nil {
+			child = &structures.DirTree{Path: file.Path, Depth: depth + 1, Err: err}
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	return node, nil
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+type ChangeRecord struct {
+	Change string   `json:"change"`
+	Path   string   `json:"path"`
+	From   string   `json:"from,omitempty"`
+	Size   int64    `json:"size"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+func NewChangeRecord(change structures.FileChange) ChangeRecord {
+	return ChangeRecord{
+		Change: change.Kind.String(),
+		Path:   change.Path,
+		From:   change.From,
+		Size:   change.Size,
+		Fields: change.Fields,
+	}
+}
+
+func ChangesSummary(changes []structures.FileChange) string {
+	var counts [4]int
+	for _, change := range changes {
+		counts[change.Kind]++
+	}
+	return fmt.Sprintf("%d added, %d removed, %d modified, %d moved",
+		counts[structures.Added], counts[structures.Removed], counts[structures.Modified], counts[structures.Moved])
+}
+
+func WriteChanges(w io.Writer, changes []structures.FileChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "✨ No differences found")
+		return
+	}
+
+	for _, change := range changes {
+		switch change.Kind {
+		case structures.Added:
+			fmt.Fprintf(w, "\033[32m+ %s\033[0m (%s)\n", change.Path, humanizeSize(change.Size))
+		case structures.Removed:
+			fmt.Fprintf(w, "\033[31m- %s\033[0m\n", change.Path)
+		case structures.Modified:
+			fmt.Fprintf(w, "\033[33m~ %s\033[0m (%s)\n", change.Path, strings.Join(change.Fields, ", "))
+		case structures.Moved:
+			fmt.Fprintf(w, "\033[36m→ %s -> %s\033[0m\n", change.From, change.Path)
+		}
+	}
+	fmt.Fprintf(w, "\n%s\n", ChangesSummary(changes))
+}
+
+func WriteChangesJSON(w io.Writer, changes []structures.FileChange, format Format) error {
+	records := make([]ChangeRecord, 0, len(changes))
+	for _, change := range changes {
+		records = append(records, NewChangeRecord(change))
+	}
+
+	if format == FormatNDJSON {
+		enc := json.NewEncoder(w)
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
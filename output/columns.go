@@ -0,0 +1,148 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+type Column struct {
+	Name   string
+	Header string
+	Value  func(file structures.FileInfo) string
+}
+
+var DefaultColumns = []string{"name", "size", "mtime", "perms", "owner", "path"}
+
+var columns = map[string]Column{
+	"name": {
+		Name:   "name",
+		Header: "Name",
+		Value:  func(file structures.FileInfo) string { return file.Name },
+	},
+	"type": {
+		Name:   "type",
+		Header: "Type",
+		Value:  TypeName,
+	},
+	"size": {
+		Name:   "size",
+		Header: "Size",
+		Value:  func(file structures.FileInfo) string { return strconv.FormatInt(file.RawSize, 10) },
+	},
+	"mtime": {
+		Name:   "mtime",
+		Header: "Modified",
+		Value:  func(file structures.FileInfo) string { return formatRFC3339(file.ModTime) },
+	},
+	"atime": {
+		Name:   "atime",
+		Header: "Accessed",
+		Value:  func(file structures.FileInfo) string { return formatRFC3339(file.AccessTime) },
+	},
+	"ctime": {
+		Name:   "ctime",
+		Header: "Changed",
+		Value:  func(file structures.FileInfo) string { return formatRFC3339(file.ChangeTime) },
+	},
+	"birth": {
+		Name:   "birth",
+		Header: "Created",
+		Value:  func(file structures.FileInfo) string { return formatRFC3339(file.BirthTime) },
+	},
+	"perms": {
+		Name:   "perms",
+		Header: "Permissions",
+		Value:  func(file structures.FileInfo) string { return file.Permissions },
+	},
+	"owner": {
+		Name:   "owner",
+		Header: "Owner",
+		Value:  func(file structures.FileInfo) string { return file.UserAndGroup },
+	},
+	"target": {
+		Name:   "target",
+		Header: "Target",
+		Value:  func(file structures.FileInfo) string { return file.LinkTarget },
+	},
+	"inode": {
+		Name:   "inode",
+		Header: "Inode",
+		Value:  func(file structures.FileInfo) string { return strconv.FormatUint(file.Inode, 10) },
+	},
+	"links": {
+		Name:   "links",
+		Header: "Links",
+		Value:  func(file structures.FileInfo) string { return strconv.FormatUint(file.Links, 10) },
+	},
+	"blocks": {
+		Name:   "blocks",
+		Header: "Blocks",
+		Value:  func(file structures.FileInfo) string { return strconv.FormatInt(file.Blocks, 10) },
+	},
+	"dev": {
+		Name:   "dev",
+		Header: "Device",
+		Value:  func(file structures.FileInfo) string { return strconv.FormatUint(file.Dev, 10) },
+	},
+	"rdev": {
+		Name:   "rdev",
+		Header: "Rdev",
+		Value: func(file structures.FileInfo) string {
+			if file.Kind != structures.KindBlockDevice && file.Kind != structures.KindCharDevice {
+				return ""
+			}
+			return strconv.FormatUint(file.Rdev, 10)
+		},
+	},
+	"path": {
+		Name:   "path",
+		Header: "Path",
+		Value:  func(file structures.FileInfo) string { return file.Path },
+	},
+}
+
+func ParseColumns(spec string) ([]Column, error) {
+	names := DefaultColumns
+	if spec != "" {
+		names = strings.Split(spec, ",")
+	}
+
+	selected := make([]Column, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(strings.ToLower(name))
+		column, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		selected = append(selected, column)
+	}
+	return selected, nil
+}
+
+var StatColumns = []string{"inode", "links", "blocks", "dev", "rdev"}
+
+// TableColumns picks the stat columns out of selected; the table view shows
+// these in addition to its fixed set.
+func TableColumns(selected []Column) []Column {
+	var extra []Column
+	for _, column := range selected {
+		for _, name := range StatColumns {
+			if column.Name == name {
+				extra = append(extra, column)
+			}
+		}
+	}
+	return extra
+}
+
+func HasColumn(selected []Column, name string) bool {
+	for _, column := range selected {
+		if column.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+type DelimitedWriter struct {
+	w           *csv.Writer
+	columns     []Column
+	wroteHeader bool
+}
+
+func NewDelimitedWriter(w io.Writer, columns []Column, comma rune) *DelimitedWriter {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	return &DelimitedWriter{w: cw, columns: columns}
+}
+
+func (d *DelimitedWriter) writeHeader() error {
+	header := make([]string, len(d.columns))
+	for i, column := range d.columns {
+		header[i] = column.Name
+	}
+	d.wroteHeader = true
+	return d.w.Write(header)
+}
+
+func (d *DelimitedWriter) Write(file structures.FileInfo) error {
+	if !d.wroteHeader {
+		if err := d.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	row := make([]string, len(d.columns))
+	for i, column := range d.columns {
+		row[i] = column.Value(file)
+	}
+	if err := d.w.Write(row); err != nil {
+		return err
+	}
+
+	d.w.Flush()
+	return d.w.Error()
+}
+
+func (d *DelimitedWriter) Flush() error {
+	if !d.wroteHeader {
+		if err := d.writeHeader(); err != nil {
+			return err
+		}
+	}
+	d.w.Flush()
+	return d.w.Error()
+}
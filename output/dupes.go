@@ -0,0 +1,78 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+type DuplicateRecord struct {
+	Size   int64    `json:"size"`
+	Hash   string   `json:"hash"`
+	Wasted int64    `json:"wasted"`
+	Paths  []string `json:"paths"`
+}
+
+func NewDuplicateRecord(set structures.DuplicateSet) DuplicateRecord {
+	record := DuplicateRecord{Size: set.Size, Hash: set.Hash, Wasted: set.Wasted()}
+	for _, file := range set.Files {
+		record.Paths = append(record.Paths, file.Path)
+	}
+	return record
+}
+
+func DuplicatesSummary(sets []structures.DuplicateSet) string {
+	var wasted int64
+	files := 0
+	for _, set := range sets {
+		wasted += set.Wasted()
+		files += len(set.Files)
+	}
+	return fmt.Sprintf("%d duplicate sets, %d files, %s wasted", len(sets), files, humanizeSize(wasted))
+}
+
+func WriteDuplicates(w io.Writer, sets []structures.DuplicateSet) {
+	if len(sets) == 0 {
+		fmt.Fprintln(w, "✨ No duplicate files found")
+		return
+	}
+
+	for _, set := range sets {
+		fmt.Fprintf(w, "\n🧬 %d copies of %s, %s wasted (sha256 %.12s)\n",
+			len(set.Files), humanizeSize(set.Size), humanizeSize(set.Wasted()), set.Hash)
+		for i, file := range set.Files {
+			connector := "├── "
+			if i == len(set.Files)-1 {
+				connector = "└── "
+			}
+			fmt.Fprintf(w, "%s%s\n", connector, file.Path)
+		}
+	}
+	fmt.Fprintf(w, "\n%s\n", DuplicatesSummary(sets))
+}
+
+func WriteDuplicatesJSON(w io.Writer, sets []structures.DuplicateSet, format Format) error {
+	records := make([]DuplicateRecord, 0, len(sets))
+	for _, set := range sets {
+		records = append(records, NewDuplicateRecord(set))
+	}
+
+	if format == FormatNDJSON {
+		enc := json.NewEncoder(w)
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,29 @@
+package output
+
+import "fmt"
+
+type Format string
+
+const (
+	FormatTable  Format = "table"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+	FormatTSV    Format = "tsv"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatTable, FormatJSON, FormatNDJSON, FormatCSV, FormatTSV:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("unknown format %q (expected table, json, ndjson, csv or tsv)", s)
+}
+
+func (f Format) IsMachine() bool {
+	return f != FormatTable
+}
+
+func (f Format) IsDelimited() bool {
+	return f == FormatCSV || f == FormatTSV
+}
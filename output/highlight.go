@@ -0,0 +1,63 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+const (
+	highlightStart = "\033[1;36m"
+	highlightEnd   = "\033[0m"
+)
+
+func Highlight(text string, positions []int) string {
+	if len(positions) == 0 {
+		return text
+	}
+
+	marked := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		marked[pos] = true
+	}
+
+	var sb strings.Builder
+	for i, r := range []rune(text) {
+		if marked[i] {
+			sb.WriteString(highlightStart)
+			sb.WriteRune(r)
+			sb.WriteString(highlightEnd)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func HighlightSpans(text string, spans [][2]int) string {
+	var sb strings.Builder
+	last := 0
+	for _, span := range spans {
+		if span[0] < last || span[1] > len(text) {
+			continue
+		}
+		sb.WriteString(text[last:span[0]])
+		sb.WriteString(highlightStart)
+		sb.WriteString(text[span[0]:span[1]])
+		sb.WriteString(highlightEnd)
+		last = span[1]
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+func WriteLineMatches(w io.Writer, files []structures.FileInfo) {
+	for _, file := range files {
+		fmt.Fprintf(w, "📄 %s\n", file.Path)
+		for _, m := range file.Lines {
+			fmt.Fprintf(w, "  %d: %s\n", m.Line, HighlightSpans(m.Text, m.Spans))
+		}
+	}
+}
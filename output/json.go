@@ -0,0 +1,132 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+type Record struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	Size        int64  `json:"size"`
+	Permissions string `json:"permissions"`
+	User        string `json:"user,omitempty"`
+	Group       string `json:"group,omitempty"`
+	ModTime     string `json:"mtime"`
+	AccessTime  string `json:"atime,omitempty"`
+	ChangeTime  string `json:"ctime,omitempty"`
+	BirthTime   string `json:"birth,omitempty"`
+	Hidden      bool   `json:"hidden"`
+	Inode       uint64 `json:"inode"`
+	Links       uint64 `json:"links"`
+	Blocks      int64  `json:"blocks"`
+	Dev         uint64 `json:"dev"`
+	Rdev        uint64 `json:"rdev,omitempty"`
+	Target      string `json:"target,omitempty"`
+	Broken      bool   `json:"broken,omitempty"`
+	Score       int    `json:"score,omitempty"`
+	Matches     []int  `json:"matches,omitempty"`
+	Lines       []Line `json:"lines,omitempty"`
+}
+
+type Line struct {
+	Line  int      `json:"line"`
+	Text  string   `json:"text"`
+	Spans [][2]int `json:"spans"`
+}
+
+func NewRecord(file structures.FileInfo) Record {
+	user, group, _ := strings.Cut(file.UserAndGroup, ":")
+
+	var lines []Line
+	for _, m := range file.Lines {
+		lines = append(lines, Line{Line: m.Line, Text: m.Text, Spans: m.Spans})
+	}
+
+	return Record{
+		Name:        file.Name,
+		Path:        file.Path,
+		Type:        TypeName(file),
+		Size:        file.RawSize,
+		Permissions: file.Permissions,
+		User:        user,
+		Group:       group,
+		ModTime:     formatRFC3339(file.ModTime),
+		AccessTime:  formatRFC3339(file.AccessTime),
+		ChangeTime:  formatRFC3339(file.ChangeTime),
+		BirthTime:   formatRFC3339(file.BirthTime),
+		Hidden:      file.Hidden,
+		Inode:       file.Inode,
+		Links:       file.Links,
+		Blocks:      file.Blocks,
+		Dev:         file.Dev,
+		Rdev:        file.Rdev,
+		Target:      file.LinkTarget,
+		Broken:      file.Broken,
+		Score:       file.Score,
+		Matches:     file.MatchPositions,
+		Lines:       lines,
+	}
+}
+
+func formatRFC3339(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+type JSONWriter struct {
+	w     io.Writer
+	count int
+}
+
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{w: w}
+}
+
+func (j *JSONWriter) Write(file structures.FileInfo) error {
+	data, err := json.MarshalIndent(NewRecord(file), "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n  "
+	if j.count == 0 {
+		sep = "[\n  "
+	}
+	j.count++
+
+	_, err = io.WriteString(j.w, sep+string(data))
+	return err
+}
+
+func (j *JSONWriter) Flush() error {
+	closing := "\n]\n"
+	if j.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
+
+type NDJSONWriter struct {
+	enc *json.Encoder
+}
+
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{enc: json.NewEncoder(w)}
+}
+
+func (n *NDJSONWriter) Write(file structures.FileInfo) error {
+	return n.enc.Encode(NewRecord(file))
+}
+
+func (n *NDJSONWriter) Flush() error {
+	return nil
+}
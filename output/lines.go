@@ -0,0 +1,53 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/rinimisini112/gls/structures"
+)
+
+type LineWriter struct {
+	w         io.Writer
+	timeField string
+	timeStyle string
+	withOwner bool
+	classify  bool
+	extra     []Column
+}
+
+// NewLineWriter prints one line per file in the order they arrive. extra
+// holds the stat columns picked with --columns, which follow the time column
+// as they do in the table view.
+func NewLineWriter(w io.Writer, timeField, timeStyle string, withOwner, classify bool, extra []Column) *LineWriter {
+	return &LineWriter{w: w, timeField: timeField, timeStyle: timeStyle, withOwner: withOwner, classify: classify, extra: extra}
+}
+
+func (l *LineWriter) Write(file structures.FileInfo) error {
+	owner := ""
+	if l.withOwner {
+		owner = fmt.Sprintf("%-20s ", file.UserAndGroup)
+	}
+
+	var extra strings.Builder
+	for _, column := range l.extra {
+		fmt.Fprintf(&extra, "%10s  ", column.Value(file))
+	}
+
+	_, err := fmt.Fprintf(l.w, "%-7s %s%-10s %9s  %-20s %s%s\n",
+		TypeLabel(file),
+		owner,
+		file.Permissions,
+		humanize.Bytes(uint64(file.RawSize)),
+		FormatTime(file.Time(l.timeField), l.timeStyle),
+		extra.String(),
+		DisplayName(file, l.classify),
+	)
+	return err
+}
+
+func (l *LineWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,58 @@
+package output
+
+import "github.com/rinimisini112/gls/structures"
+
+const brokenStart = "\033[1;31m"
+
+var kindLabels = map[structures.FileKind][2]string{
+	structures.KindFile:        {"📄", "File"},
+	structures.KindDir:         {"📂", "Dir"},
+	structures.KindSymlink:     {"🔗", "Link"},
+	structures.KindSocket:      {"🔌", "Socket"},
+	structures.KindFIFO:        {"🚰", "FIFO"},
+	structures.KindBlockDevice: {"💽", "Block"},
+	structures.KindCharDevice:  {"🖨️", "Char"},
+	structures.KindExecutable:  {"⚙️", "Exec"},
+}
+
+func TypeName(file structures.FileInfo) string {
+	if file.IsLink {
+		return structures.KindSymlink.String()
+	}
+	return file.Kind.String()
+}
+
+func typeLabel(file structures.FileInfo) [2]string {
+	switch {
+	case file.Broken:
+		return [2]string{"💔", "Broken"}
+	case file.IsLink:
+		return kindLabels[structures.KindSymlink]
+	}
+	return kindLabels[file.Kind]
+}
+
+func TypeIcon(file structures.FileInfo) string {
+	return typeLabel(file)[0]
+}
+
+func TypeLabel(file structures.FileInfo) string {
+	label := typeLabel(file)
+	return label[0] + " " + label[1]
+}
+
+func DisplayName(file structures.FileInfo, classify bool) string {
+	name := file.Name
+	if classify {
+		name += file.Kind.Indicator()
+	}
+	if !file.IsLink {
+		return name
+	}
+
+	name += " -> " + file.LinkTarget
+	if file.Broken {
+		return brokenStart + name + highlightEnd
+	}
+	return name
+}
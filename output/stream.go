@@ -0,0 +1,25 @@
+package output
+
+import (
+	"io"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+type StreamWriter interface {
+	Write(file structures.FileInfo) error
+	Flush() error
+}
+
+func NewStreamWriter(format Format, w io.Writer, columns []Column) StreamWriter {
+	switch format {
+	case FormatCSV:
+		return NewDelimitedWriter(w, columns, ',')
+	case FormatTSV:
+		return NewDelimitedWriter(w, columns, '\t')
+	case FormatJSON:
+		return NewJSONWriter(w)
+	default:
+		return NewNDJSONWriter(w)
+	}
+}
@@ -0,0 +1,114 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+var strftimeLayouts = map[byte]string{
+	'Y': "2006",
+	'y': "06",
+	'm': "01",
+	'd': "02",
+	'e': "_2",
+	'H': "15",
+	'I': "03",
+	'M': "04",
+	'S': "05",
+	'p': "PM",
+	'b': "Jan",
+	'h': "Jan",
+	'B': "January",
+	'a': "Mon",
+	'A': "Monday",
+	'Z': "MST",
+	'z': "-0700",
+	'F': "2006-01-02",
+	'T': "15:04:05",
+	'R': "15:04",
+	'j': "002",
+	'%': "%",
+}
+
+func ParseTimeStyle(style string) error {
+	switch style {
+	case "", "iso", "relative", "full":
+		return nil
+	}
+	if strings.HasPrefix(style, "+") {
+		_, err := strftime(time.Time{}, style[1:])
+		return err
+	}
+	return fmt.Errorf("unknown time style %q (expected iso, relative, full or +FORMAT)", style)
+}
+
+func FormatTime(t time.Time, style string) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	switch {
+	case style == "iso":
+		return t.Format("2006-01-02 15:04")
+	case style == "full":
+		return t.Format("2006-01-02 15:04:05.000000000 -0700")
+	case style == "relative":
+		return humanize.Time(t)
+	case strings.HasPrefix(style, "+"):
+		formatted, err := strftime(t, style[1:])
+		if err != nil {
+			return t.Format(time.RFC822)
+		}
+		return formatted
+	default:
+		return t.Format(time.RFC822)
+	}
+}
+
+func TimeHeader(field string) string {
+	switch field {
+	case "atime":
+		return "Last Accessed"
+	case "ctime":
+		return "Last Changed"
+	case "birth":
+		return "Created"
+	default:
+		return "Last Modified"
+	}
+}
+
+func ParseTimeField(field string) error {
+	switch field {
+	case "mtime", "atime", "ctime", "birth":
+		return nil
+	}
+	return fmt.Errorf("unknown time field %q (expected mtime, atime, ctime or birth)", field)
+}
+
+func strftime(t time.Time, format string) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			out.WriteByte(format[i])
+			continue
+		}
+		if i+1 >= len(format) {
+			return "", fmt.Errorf("dangling %% in time format %q", format)
+		}
+		i++
+		layout, ok := strftimeLayouts[format[i]]
+		if !ok {
+			return "", fmt.Errorf("unsupported directive %%%c in time format %q", format[i], format)
+		}
+		if layout == "%" {
+			out.WriteByte('%')
+			continue
+		}
+		out.WriteString(t.Format(layout))
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,89 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize"
+	"github.com/rinimisini112/gls/structures"
+)
+
+func TreeSummary(root *structures.DirTree) string {
+	var files, dirs int
+	var size int64
+	root.Walk(func(node *structures.DirTree) {
+		files += node.FileCount
+		dirs += node.DirCount
+		size += node.TotalSize
+	})
+	return fmt.Sprintf("%d directories, %d files, %s", dirs, files, humanizeSize(size))
+}
+
+func DirSummary(node *structures.DirTree) string {
+	return fmt.Sprintf("%d files, %d dirs, %s", node.FileCount, node.DirCount, humanizeSize(node.TotalSize))
+}
+
+func WriteTree(w io.Writer, root *structures.DirTree, classify bool) {
+	fmt.Fprintf(w, "📂 %s (%s)\n", root.Path, DirSummary(root))
+	writeTreeLevel(w, root, "", classify)
+	fmt.Fprintf(w, "\n%s\n", TreeSummary(root))
+}
+
+func writeTreeLevel(w io.Writer, node *structures.DirTree, prefix string, classify bool) {
+	if node.Err != nil {
+		fmt.Fprintf(w, "%s└── ❌ %v\n", prefix, node.Err)
+		return
+	}
+
+	children := make(map[string]*structures.DirTree, len(node.Children))
+	for _, child := range node.Children {
+		children[child.Path] = child
+	}
+
+	shown := make(map[string]bool, len(node.Files))
+	for _, file := range node.Files {
+		shown[file.Path] = true
+	}
+
+	type item struct {
+		file  *structures.FileInfo
+		child *structures.DirTree
+	}
+
+	var items []item
+	for _, child := range node.Children {
+		if !shown[child.Path] {
+			items = append(items, item{child: child})
+		}
+	}
+	for i := range node.Files {
+		file := &node.Files[i]
+		items = append(items, item{file: file, child: children[file.Path]})
+	}
+
+	for i, it := range items {
+		connector, indent := "├── ", "│   "
+		if i == len(items)-1 {
+			connector, indent = "└── ", "    "
+		}
+
+		switch {
+		case it.child != nil:
+			label := filepath.Base(it.child.Path)
+			if it.child.Err == nil {
+				label = fmt.Sprintf("%s (%s)", label, DirSummary(it.child))
+			}
+			fmt.Fprintf(w, "%s%s📂 %s\n", prefix, connector, label)
+			writeTreeLevel(w, it.child, prefix+indent, classify)
+		case it.file.IsLink || it.file.IsDir:
+			fmt.Fprintf(w, "%s%s%s %s\n", prefix, connector, TypeIcon(*it.file), DisplayName(*it.file, classify))
+		default:
+			fmt.Fprintf(w, "%s%s%s %s (%s)\n", prefix, connector, TypeIcon(*it.file), DisplayName(*it.file, classify), humanizeSize(it.file.RawSize))
+		}
+	}
+}
+
+func humanizeSize(size int64) string {
+	return humanize.Bytes(uint64(size))
+}
@@ -0,0 +1,264 @@
+package query
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rinimisini112/gls/filter"
+	"github.com/rinimisini112/gls/structures"
+)
+
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindSize
+	kindTime
+	kindInt
+	kindPerm
+	kindType
+	kindBool
+)
+
+var kindOperators = map[fieldKind][]string{
+	kindString: {"=", "!=", "~", "!~", "=~"},
+	kindSize:   {"=", "!=", "<", "<=", ">", ">="},
+	kindTime:   {"=", "!=", "<", "<=", ">", ">="},
+	kindInt:    {"=", "!=", "<", "<=", ">", ">="},
+	kindPerm:   {"=", "!=", "~", ">="},
+	kindType:   {"=", "!="},
+	kindBool:   {"=", "!="},
+}
+
+type field struct {
+	name string
+	kind fieldKind
+}
+
+var fields = map[string]field{
+	"name":   {name: "name", kind: kindString},
+	"path":   {name: "path", kind: kindString},
+	"ext":    {name: "ext", kind: kindString},
+	"owner":  {name: "owner", kind: kindString},
+	"group":  {name: "group", kind: kindString},
+	"size":   {name: "size", kind: kindSize},
+	"mtime":  {name: "mtime", kind: kindTime},
+	"atime":  {name: "atime", kind: kindTime},
+	"ctime":  {name: "ctime", kind: kindTime},
+	"birth":  {name: "birth", kind: kindTime},
+	"depth":  {name: "depth", kind: kindInt},
+	"perms":  {name: "perms", kind: kindPerm},
+	"type":   {name: "type", kind: kindType},
+	"hidden": {name: "hidden", kind: kindBool},
+}
+
+func lookupField(name string) (field, bool) {
+	f, ok := fields[strings.ToLower(name)]
+	return f, ok
+}
+
+func fieldNames() string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func (f field) supports(op string) bool {
+	for _, candidate := range kindOperators[f.kind] {
+		if candidate == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (f field) opList() string {
+	return strings.Join(kindOperators[f.kind], " ")
+}
+
+func (f field) stringValue(file structures.FileInfo) string {
+	switch f.name {
+	case "name":
+		return file.Name
+	case "path":
+		return filepath.ToSlash(file.Path)
+	case "ext":
+		return strings.TrimPrefix(filepath.Ext(file.Name), ".")
+	case "owner":
+		owner, _, _ := strings.Cut(file.UserAndGroup, ":")
+		return owner
+	case "group":
+		_, group, _ := strings.Cut(file.UserAndGroup, ":")
+		return group
+	}
+	return ""
+}
+
+func (f field) compile(p *parser, op string, value token) (filter.Predicate, error) {
+	switch f.kind {
+	case kindString:
+		return f.compileString(p, op, value)
+	case kindSize:
+		size, err := filter.ParseSize(value.text)
+		if err != nil {
+			return nil, p.errorf(value, "invalid size %q (expected e.g. 512, 10k, 1.5M)", value.text)
+		}
+		return compareInt(op, size, func(file structures.FileInfo) int64 { return file.RawSize }), nil
+	case kindInt:
+		n, err := strconv.ParseInt(value.text, 10, 64)
+		if err != nil {
+			return nil, p.errorf(value, "invalid number %q", value.text)
+		}
+		return compareInt(op, n, func(file structures.FileInfo) int64 { return int64(file.Depth) }), nil
+	case kindTime:
+		return f.compileTime(p, op, value)
+	case kindPerm:
+		return compilePerm(p, op, value)
+	case kindType:
+		want := strings.ToLower(value.text)
+		if _, ok := structures.ParseKind(want); !ok && want != "broken" && want != "device" {
+			return nil, p.errorf(value, "invalid type %q (expected file, dir, link, broken, exec, socket, fifo, block, char or device)", value.text)
+		}
+		return negateIf(op == "!=", func(file structures.FileInfo) bool {
+			return file.IsType(want)
+		}), nil
+	case kindBool:
+		want, err := strconv.ParseBool(value.text)
+		if err != nil {
+			return nil, p.errorf(value, "invalid boolean %q (expected true or false)", value.text)
+		}
+		return negateIf(op == "!=", func(file structures.FileInfo) bool {
+			return file.Hidden == want
+		}), nil
+	}
+	return nil, p.errorf(value, "unsupported field %q", f.name)
+}
+
+func (f field) compileString(p *parser, op string, value token) (filter.Predicate, error) {
+	switch op {
+	case "=", "!=":
+		want := value.text
+		if f.name == "ext" {
+			want = strings.TrimPrefix(want, ".")
+		}
+		return negateIf(op == "!=", func(file structures.FileInfo) bool {
+			return f.stringValue(file) == want
+		}), nil
+	case "~", "!~":
+		glob, err := filter.CompileGlob(value.text)
+		if err != nil {
+			return nil, p.errorf(value, "%v", err)
+		}
+		return negateIf(op == "!~", func(file structures.FileInfo) bool {
+			return glob.Match(f.stringValue(file))
+		}), nil
+	default:
+		re, err := regexp.Compile(value.text)
+		if err != nil {
+			return nil, p.errorf(value, "invalid regular expression: %v", err)
+		}
+		return func(file structures.FileInfo) bool {
+			return re.MatchString(f.stringValue(file))
+		}, nil
+	}
+}
+
+func (f field) compileTime(p *parser, op string, value token) (filter.Predicate, error) {
+	ref, err := filter.ParseTime(value.text, time.Now())
+	if err != nil {
+		return nil, p.errorf(value, "invalid time %q (expected a date like 2025-01-01 or an age like 2d)", value.text)
+	}
+
+	name := f.name
+	if op == "=" || op == "!=" {
+		y, m, d := ref.Local().Date()
+		return negateIf(op == "!=", func(file structures.FileInfo) bool {
+			fy, fm, fd := file.Time(name).Local().Date()
+			return fy == y && fm == m && fd == d
+		}), nil
+	}
+
+	return func(file structures.FileInfo) bool {
+		t := file.Time(name)
+		switch op {
+		case "<":
+			return t.Before(ref)
+		case "<=":
+			return !t.After(ref)
+		case ">":
+			return t.After(ref)
+		default:
+			return !t.Before(ref)
+		}
+	}, nil
+}
+
+func compilePerm(p *parser, op string, value token) (filter.Predicate, error) {
+	bits, err := parsePermValue(value.text)
+	if err != nil {
+		return nil, p.errorf(value, "invalid permissions %q (expected e.g. 644, u+x or rwxr-xr-x)", value.text)
+	}
+
+	return func(file structures.FileInfo) bool {
+		perm := file.Mode.Perm()
+		switch op {
+		case "=":
+			return perm == bits
+		case "!=":
+			return perm != bits
+		case "~":
+			return perm&bits != 0
+		default:
+			return perm&bits == bits
+		}
+	}, nil
+}
+
+func parsePermValue(s string) (os.FileMode, error) {
+	symbolic := strings.TrimPrefix(s, "-")
+	if len(symbolic) == 9 && strings.Trim(symbolic, "rwx-") == "" {
+		var bits os.FileMode
+		for i, c := range symbolic {
+			if c != '-' {
+				bits |= 1 << (8 - i)
+			}
+		}
+		return bits, nil
+	}
+	return filter.ParsePerm(s)
+}
+
+func compareInt(op string, want int64, get func(structures.FileInfo) int64) filter.Predicate {
+	return func(file structures.FileInfo) bool {
+		got := get(file)
+		switch op {
+		case "=":
+			return got == want
+		case "!=":
+			return got != want
+		case "<":
+			return got < want
+		case "<=":
+			return got <= want
+		case ">":
+			return got > want
+		default:
+			return got >= want
+		}
+	}
+}
+
+func negateIf(negate bool, pred filter.Predicate) filter.Predicate {
+	if !negate {
+		return pred
+	}
+	return func(file structures.FileInfo) bool { return !pred(file) }
+}
@@ -0,0 +1,133 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+var operators = []string{"=~", "!~", "==", "!=", "<=", ">=", "=", "<", ">", "~"}
+
+type Error struct {
+	Input string
+	Pos   int
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at column %d\n  %s\n  %s^", e.Msg, e.Pos+1, e.Input, strings.Repeat(" ", e.Pos))
+}
+
+func lex(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+
+	for i < len(input) {
+		c := input[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case c == '"' || c == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for i < len(input) && input[i] != c {
+				if input[i] == '\\' && i+1 < len(input) {
+					i++
+				}
+				sb.WriteByte(input[i])
+				i++
+			}
+			if i >= len(input) {
+				return nil, &Error{Input: input, Pos: start, Msg: "unterminated string"}
+			}
+			i++
+			tokens = append(tokens, token{kind: tokString, text: sb.String(), pos: start})
+		case strings.HasPrefix(input[i:], "&&"):
+			tokens = append(tokens, token{kind: tokAnd, text: "&&", pos: i})
+			i += 2
+		case strings.HasPrefix(input[i:], "||"):
+			tokens = append(tokens, token{kind: tokOr, text: "||", pos: i})
+			i += 2
+		default:
+			if op := matchOperator(input[i:]); op != "" {
+				tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+				i += len(op)
+				continue
+			}
+			if c == '!' {
+				tokens = append(tokens, token{kind: tokNot, text: "!", pos: i})
+				i++
+				continue
+			}
+
+			start := i
+			for i < len(input) && !strings.ContainsRune(" \t\n()\"'", rune(input[i])) && matchOperator(input[i:]) == "" {
+				i++
+			}
+			word := input[start:i]
+			tokens = append(tokens, token{kind: keywordKind(word), text: word, pos: start})
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(input)})
+	return tokens, nil
+}
+
+func matchOperator(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func keywordKind(word string) tokenKind {
+	switch strings.ToLower(word) {
+	case "and":
+		return tokAnd
+	case "or":
+		return tokOr
+	case "not":
+		return tokNot
+	}
+	return tokWord
+}
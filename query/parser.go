@@ -0,0 +1,177 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/rinimisini112/gls/filter"
+	"github.com/rinimisini112/gls/structures"
+)
+
+type Query struct {
+	Source string
+	pred   filter.Predicate
+	fields map[string]bool
+}
+
+func Parse(input string) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{input: input, tokens: tokens, fields: make(map[string]bool)}
+	if p.peek().kind == tokEOF {
+		return nil, p.errorf(p.peek(), "empty query")
+	}
+
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %s, expected \"and\", \"or\" or end of query", tok.describe())
+	}
+
+	return &Query{Source: input, pred: pred, fields: p.fields}, nil
+}
+
+func (q *Query) Match(file structures.FileInfo) bool {
+	return q.pred(file)
+}
+
+func (q *Query) NeedsOwner() bool {
+	return q.fields["owner"] || q.fields["group"]
+}
+
+func (q *Query) NeedsBirth() bool {
+	return q.fields["birth"]
+}
+
+func (q *Query) String() string {
+	return q.Source
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+	fields map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return &Error{Input: p.input, Pos: tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (filter.Predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(file structures.FileInfo) bool { return l(file) || right(file) }
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (filter.Predicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(file structures.FileInfo) bool { return l(file) && right(file) }
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (filter.Predicate, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(file structures.FileInfo) bool { return !inner(file) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (filter.Predicate, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.errorf(closing, "expected \")\" to close \"(\" at column %d, found %s", tok.pos+1, closing.describe())
+		}
+		return inner, nil
+	case tokWord:
+		return p.parseComparison(tok)
+	case tokEOF:
+		return nil, p.errorf(tok, "unexpected end of query, expected a condition")
+	default:
+		return nil, p.errorf(tok, "unexpected %s, expected a field name or \"(\"", tok.describe())
+	}
+}
+
+func (p *parser) parseComparison(fieldTok token) (filter.Predicate, error) {
+	field, ok := lookupField(fieldTok.text)
+	if !ok {
+		return nil, p.errorf(fieldTok, "unknown field %q (expected one of %s)", fieldTok.text, fieldNames())
+	}
+	p.fields[field.name] = true
+
+	opTok := p.peek()
+	if opTok.kind != tokOp {
+		if field.kind == kindBool {
+			return field.compile(p, "=", token{kind: tokWord, text: "true", pos: fieldTok.pos})
+		}
+		return nil, p.errorf(opTok, "expected a comparison operator after %q, found %s", fieldTok.text, opTok.describe())
+	}
+	p.next()
+
+	valueTok := p.next()
+	if valueTok.kind != tokWord && valueTok.kind != tokString {
+		return nil, p.errorf(valueTok, "expected a value after %q, found %s", opTok.text, valueTok.describe())
+	}
+
+	op := opTok.text
+	if op == "==" {
+		op = "="
+	}
+	if !field.supports(op) {
+		return nil, p.errorf(opTok, "operator %q is not supported for field %q (use %s)", opTok.text, field.name, field.opList())
+	}
+
+	return field.compile(p, op, valueTok)
+}
@@ -0,0 +1,139 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+func testFile() structures.FileInfo {
+	return structures.FileInfo{
+		Name:         "main.go",
+		Path:         "src/cmd/main.go",
+		UserAndGroup: "alice:staff",
+		RawSize:      20 << 20,
+		Mode:         0o644,
+		Kind:         structures.KindFile,
+		ModTime:      time.Now().Add(-48 * time.Hour),
+		Depth:        3,
+	}
+}
+
+func TestParseMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"size > 10M", true},
+		{"size < 10M", false},
+		{"size >= 20M and size <= 20M", true},
+		{"ext = go", true},
+		{"ext = .go", true},
+		{"ext != go", false},
+		{"name ~ '*.go'", true},
+		{"name !~ '*.go'", false},
+		{"path =~ '^src/.*\\.go$'", true},
+		{"name == \"main.go\"", true},
+		{"owner = alice and group = staff", true},
+		{"owner = bob or group = staff", true},
+		{"owner = bob || group = wheel", false},
+		{"not owner = bob", true},
+		{"!(owner = alice)", false},
+		{"depth = 3", true},
+		{"depth > 3", false},
+		{"perms = 644", true},
+		{"perms >= u+r", true},
+		{"perms ~ o+w", false},
+		{"type = file", true},
+		{"type != dir", true},
+		{"hidden", false},
+		{"not hidden", true},
+		{"hidden = false", true},
+		{"mtime < 1d", true},
+		{"mtime > 7d", true},
+		{"ext = txt or (size > 1M and NOT owner = bob)", true},
+		{"ext = txt or size > 1M and owner = bob", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.query, err)
+			}
+			if got := q.Match(testFile()); got != tt.want {
+				t.Errorf("Match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{"", 0, "empty query"},
+		{"   ", 3, "empty query"},
+		{"colour = red", 0, "unknown field \"colour\""},
+		{"size 10M", 5, "expected a comparison operator"},
+		{"size >", 6, "expected a value"},
+		{"size ~ 10M", 5, "operator \"~\" is not supported"},
+		{"size > lots", 7, "invalid size"},
+		{"mtime < someday", 8, "invalid time"},
+		{"depth = deep", 8, "invalid number"},
+		{"perms = rwz", 8, "invalid permissions"},
+		{"type = folder", 7, "invalid type"},
+		{"hidden = maybe", 9, "invalid boolean"},
+		{"path =~ '('", 8, "invalid regular expression"},
+		{"name = 'main.go", 7, "unterminated string"},
+		{"(size > 1M", 10, "expected \")\" to close \"(\" at column 1"},
+		{"size > 1M and", 13, "unexpected end of query"},
+		{"size > 1M ext = go", 10, "expected \"and\", \"or\" or end of query"},
+		{"and size > 1M", 0, "expected a field name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var qerr *Error
+			if !errors.As(err, &qerr) {
+				t.Fatalf("Parse(%q) error = %v, want *Error", tt.query, err)
+			}
+			if qerr.Pos != tt.pos {
+				t.Errorf("Pos = %d, want %d", qerr.Pos, tt.pos)
+			}
+			if !strings.Contains(qerr.Msg, tt.msg) {
+				t.Errorf("Msg = %q, want it to contain %q", qerr.Msg, tt.msg)
+			}
+		})
+	}
+}
+
+func TestNeedsFields(t *testing.T) {
+	tests := []struct {
+		query      string
+		needsOwner bool
+		needsBirth bool
+	}{
+		{"size > 1M", false, false},
+		{"owner = root", true, false},
+		{"not group = wheel", true, false},
+		{"birth > 2d or ext = go", false, true},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		if q.NeedsOwner() != tt.needsOwner || q.NeedsBirth() != tt.needsBirth {
+			t.Errorf("%q: NeedsOwner = %v, NeedsBirth = %v, want %v, %v",
+				tt.query, q.NeedsOwner(), q.NeedsBirth(), tt.needsOwner, tt.needsBirth)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/rinimisini112/gls/filter"
+	"github.com/rinimisini112/gls/output"
+	"github.com/rinimisini112/gls/snapshot"
+	"github.com/rinimisini112/gls/structures"
+)
+
+func showSnapshotHelp() {
+	fmt.Println("\n📸 Usage: gls snapshot save <name> [directory]  Record a listing of directory (default .)")
+	fmt.Println("       gls snapshot diff <a> [b]  Compare two snapshots, or a snapshot with its directory today")
+	fmt.Println("       gls diff-dirs <dirA> <dirB>  Compare two directory trees")
+	fmt.Println("Snapshots are named or given as a path to a .json file. Diffing against the live")
+	fmt.Println("directory reuses the options the snapshot was saved with.")
+	fmt.Println("Options:")
+	fmt.Println("  --hash       Hash file contents with sha256 to catch changes that keep size and mtime")
+	fmt.Println("  --ignore-times  Do not report mtime changes (useful for copies)")
+	fmt.Println("  --format=table|json|ndjson  Output format for diffs (default table)")
+	fmt.Println("  -a           Include hidden files")
+	fmt.Println("  --no-ignore  Do not honor .gitignore, .ignore and .glsignore")
+	fmt.Println("  --include=GLOB, --exclude=GLOB  Limit which paths are recorded")
+}
+
+type snapshotFlags struct {
+	opts     snapshot.Options
+	diff     snapshot.DiffOptions
+	format   output.Format
+	walk     bool
+	operands []string
+}
+
+func parseSnapshotFlags(args []string) (snapshotFlags, bool) {
+	flags := snapshotFlags{format: output.FormatTable}
+
+	for _, arg := range args {
+		var err error
+		switch {
+		case arg == "--help" || arg == "-h":
+			showSnapshotHelp()
+			return flags, false
+		case arg == "--hash":
+			flags.opts.Hash = true
+		case arg == "--ignore-times":
+			flags.diff.IgnoreTimes = true
+		case strings.HasPrefix(arg, "--format="):
+			flags.format, err = output.ParseFormat(strings.TrimPrefix(arg, "--format="))
+			if err == nil && flags.format.IsDelimited() {
+				err = fmt.Errorf("diffs support table, json and ndjson output")
+			}
+		case arg == "-a":
+			flags.opts.ShowHidden = true
+			flags.walk = true
+		case arg == "--no-ignore":
+			flags.opts.NoIgnore = true
+			flags.walk = true
+		case strings.HasPrefix(arg, "--include="):
+			pattern := strings.TrimPrefix(arg, "--include=")
+			_, err = filter.CompileGlob(pattern)
+			flags.opts.Include = append(flags.opts.Include, pattern)
+			flags.walk = true
+		case strings.HasPrefix(arg, "--exclude="):
+			pattern := strings.TrimPrefix(arg, "--exclude=")
+			_, err = filter.CompileGlob(pattern)
+			flags.opts.Exclude = append(flags.opts.Exclude, pattern)
+			flags.walk = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Println("❌ Invalid option:", arg)
+			showSnapshotHelp()
+			return flags, false
+		default:
+			flags.operands = append(flags.operands, arg)
+		}
+		if err != nil {
+			fmt.Println("❌ Error:", err)
+			return flags, false
+		}
+	}
+
+	return flags, true
+}
+
+func runSnapshot(args []string) {
+	if len(args) == 0 {
+		showSnapshotHelp()
+		return
+	}
+
+	flags, ok := parseSnapshotFlags(args[1:])
+	if !ok {
+		return
+	}
+
+	switch args[0] {
+	case "save":
+		saveSnapshot(flags)
+	case "diff":
+		diffSnapshots(flags)
+	case "--help", "-h":
+		showSnapshotHelp()
+	default:
+		fmt.Println("❌ Unknown snapshot command:", args[0])
+		showSnapshotHelp()
+	}
+}
+
+func saveSnapshot(flags snapshotFlags) {
+	if len(flags.operands) < 1 || len(flags.operands) > 2 {
+		fmt.Println("❌ Error: usage: gls snapshot save <name> [directory]")
+		return
+	}
+
+	name, dir := flags.operands[0], "."
+	if len(flags.operands) == 2 {
+		dir = flags.operands[1]
+	}
+
+	path, err := snapshot.Path(name)
+	if err != nil {
+		fmt.Println("❌ Error:", err)
+		return
+	}
+
+	snap, ok := takeSnapshot(filepath.Base(strings.TrimSuffix(name, ".json")), dir, flags.opts)
+	if !ok {
+		return
+	}
+
+	if err := snap.Save(path); err != nil {
+		fmt.Println("❌ Error saving snapshot:", err)
+		return
+	}
+	fmt.Printf("📸 Saved %d entries of %s to %s\n", len(snap.Entries), snap.Root, path)
+}
+
+// takeSnapshot reports unreadable entries but still returns the partial
+// snapshot; it only fails when the walk could not start at all.
+func takeSnapshot(name, dir string, opts snapshot.Options) (*snapshot.Snapshot, bool) {
+	snap, err := snapshot.Take(context.Background(), name, dir, opts)
+	if snap == nil {
+		fmt.Println("❌ Error:", err)
+		return nil, false
+	}
+	reportSearchError(err)
+	return snap, true
+}
+
+func loadSnapshot(name string) (*snapshot.Snapshot, error) {
+	path, err := snapshot.Path(name)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Load(path)
+}
+
+func diffSnapshots(flags snapshotFlags) {
+	if len(flags.operands) < 1 || len(flags.operands) > 2 {
+		fmt.Println("❌ Error: usage: gls snapshot diff <a> [b]")
+		return
+	}
+
+	a, err := loadSnapshot(flags.operands[0])
+	if err != nil {
+		fmt.Println("❌ Error:", err)
+		return
+	}
+
+	var b *snapshot.Snapshot
+	if len(flags.operands) == 2 {
+		if b, err = loadSnapshot(flags.operands[1]); err != nil {
+			fmt.Println("❌ Error:", err)
+			return
+		}
+		if !a.Options.Equal(b.Options) {
+			fmt.Printf("❌ Error: %s was saved with %s but %s with %s\n", a.Name, a.Options, b.Name, b.Options)
+			return
+		}
+	} else {
+		if flags.walk && !flags.opts.Equal(a.Options) {
+			fmt.Printf("❌ Error: %s was saved with %s, drop the walk options to diff against %s\n", a.Name, a.Options, a.Root)
+			return
+		}
+		opts := a.Options
+		opts.Hash = flags.opts.Hash || a.Hashed
+		var ok bool
+		if b, ok = takeSnapshot("now", a.Root, opts); !ok {
+			return
+		}
+	}
+
+	if !flags.format.IsMachine() {
+		fmt.Printf("📸 %s (%s, %s) → %s (%s, %s)\n",
+			a.Name, a.Root, humanize.Time(a.Taken), b.Name, b.Root, humanize.Time(b.Taken))
+	}
+	writeChanges(snapshot.Diff(a, b, flags.diff), flags.format)
+}
+
+func runDiffDirs(args []string) {
+	flags, ok := parseSnapshotFlags(args)
+	if !ok {
+		return
+	}
+	if len(flags.operands) != 2 {
+		fmt.Println("❌ Error: usage: gls diff-dirs <dirA> <dirB>")
+		return
+	}
+
+	snaps := make([]*snapshot.Snapshot, 2)
+	for i, dir := range flags.operands {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			fmt.Println("❌ Error: not a directory:", dir)
+			return
+		}
+		if snaps[i], ok = takeSnapshot(dir, dir, flags.opts); !ok {
+			return
+		}
+	}
+
+	writeChanges(snapshot.Diff(snaps[0], snaps[1], flags.diff), flags.format)
+}
+
+func writeChanges(changes []structures.FileChange, format output.Format) {
+	if format.IsMachine() {
+		if err := output.WriteChangesJSON(os.Stdout, changes, format); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		return
+	}
+	output.WriteChanges(os.Stdout, changes)
+}
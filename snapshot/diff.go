@@ -0,0 +1,137 @@
+package snapshot
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+type DiffOptions struct {
+	IgnoreTimes bool
+}
+
+// Diff reports how the tree recorded in b differs from a. A file that
+// disappeared from one path and appeared at another with the same size, mode
+// type, mtime and (when both sides are hashed) content is reported as moved.
+// Directories are only compared by type and permissions, since their sizes
+// and mtimes change whenever their contents do.
+func Diff(a, b *Snapshot, opts DiffOptions) []structures.FileChange {
+	hashed := a.Hashed && b.Hashed
+	before := make(map[string]Entry, len(a.Entries))
+	for _, entry := range a.Entries {
+		before[entry.Path] = entry
+	}
+
+	var changes []structures.FileChange
+	var added []Entry
+	for _, entry := range b.Entries {
+		old, ok := before[entry.Path]
+		if !ok {
+			added = append(added, entry)
+			continue
+		}
+		delete(before, entry.Path)
+
+		if fields := compare(old, entry, hashed, opts); len(fields) > 0 {
+			changes = append(changes, structures.FileChange{
+				Kind:   structures.Modified,
+				Path:   entry.Path,
+				Size:   entry.Size,
+				Fields: fields,
+			})
+		}
+	}
+
+	var removed []Entry
+	for _, entry := range a.Entries {
+		if _, ok := before[entry.Path]; ok {
+			removed = append(removed, entry)
+		}
+	}
+
+	moves := make(map[string][]Entry)
+	if hashed || !opts.IgnoreTimes {
+		for _, entry := range removed {
+			if !entry.Mode.IsDir() {
+				key := moveKey(entry, hashed, opts)
+				moves[key] = append(moves[key], entry)
+			}
+		}
+	}
+
+	moved := make(map[string]bool)
+	for _, entry := range added {
+		candidates := moves[moveKey(entry, hashed, opts)]
+		if entry.Mode.IsDir() || len(candidates) == 0 {
+			changes = append(changes, structures.FileChange{Kind: structures.Added, Path: entry.Path, Size: entry.Size})
+			continue
+		}
+
+		pick := 0
+		for i, candidate := range candidates {
+			if path.Base(candidate.Path) == path.Base(entry.Path) {
+				pick = i
+				break
+			}
+		}
+		from := candidates[pick]
+		moves[moveKey(entry, hashed, opts)] = append(candidates[:pick], candidates[pick+1:]...)
+		moved[from.Path] = true
+
+		changes = append(changes, structures.FileChange{
+			Kind: structures.Moved,
+			Path: entry.Path,
+			From: from.Path,
+			Size: entry.Size,
+		})
+	}
+
+	for _, entry := range removed {
+		if !moved[entry.Path] {
+			changes = append(changes, structures.FileChange{Kind: structures.Removed, Path: entry.Path, Size: entry.Size})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func compare(a, b Entry, hashed bool, opts DiffOptions) []string {
+	var fields []string
+	if a.Mode.Type() != b.Mode.Type() {
+		return []string{"type"}
+	}
+	if a.Mode.Perm() != b.Mode.Perm() {
+		fields = append(fields, "mode")
+	}
+	if a.Mode.IsDir() {
+		return fields
+	}
+
+	if a.Size != b.Size {
+		fields = append(fields, "size")
+	}
+	if !opts.IgnoreTimes && !a.ModTime.Equal(b.ModTime) {
+		fields = append(fields, "mtime")
+	}
+	if a.Target != b.Target {
+		fields = append(fields, "target")
+	}
+	if hashed && a.Hash != b.Hash {
+		fields = append(fields, "content")
+	}
+	return fields
+}
+
+func moveKey(entry Entry, hashed bool, opts DiffOptions) string {
+	key := fmt.Sprintf("%d|%d|%s", entry.Size, entry.Mode.Type(), entry.Target)
+	if !opts.IgnoreTimes {
+		key += "|" + entry.ModTime.UTC().Format("2006-01-02T15:04:05.999999999")
+	}
+	if hashed {
+		key += "|" + entry.Hash
+	}
+	return key
+}
@@ -0,0 +1,179 @@
+package snapshot
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rinimisini112/gls/structures"
+)
+
+var (
+	t0 = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	t1 = t0.Add(time.Hour)
+)
+
+func file(path string, size int64, mtime time.Time, hash string) Entry {
+	return Entry{Path: path, Size: size, ModTime: mtime, Mode: 0o644, Hash: hash}
+}
+
+func dir(path string, mtime time.Time) Entry {
+	return Entry{Path: path, Size: 4096, ModTime: mtime, Mode: os.ModeDir | 0o755}
+}
+
+type change struct {
+	kind   structures.ChangeKind
+	path   string
+	from   string
+	fields []string
+}
+
+func summarize(changes []structures.FileChange) []change {
+	var out []change
+	for _, c := range changes {
+		out = append(out, change{c.Kind, c.Path, c.From, c.Fields})
+	}
+	return out
+}
+
+func equal(a, b []change) bool {
+	return slices.EqualFunc(a, b, func(x, y change) bool {
+		return x.kind == y.kind && x.path == y.path && x.from == y.from && slices.Equal(x.fields, y.fields)
+	})
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		hashed bool
+		opts   DiffOptions
+		a, b   []Entry
+		want   []change
+	}{
+		{
+			name: "unchanged",
+			a:    []Entry{file("a", 1, t0, "")},
+			b:    []Entry{file("a", 1, t0, "")},
+		},
+		{
+			name: "added and removed",
+			a:    []Entry{file("old", 1, t0, "")},
+			b:    []Entry{file("new", 2, t1, "")},
+			want: []change{
+				{kind: structures.Added, path: "new"},
+				{kind: structures.Removed, path: "old"},
+			},
+		},
+		{
+			name: "modified size and mtime",
+			a:    []Entry{file("a", 1, t0, "")},
+			b:    []Entry{file("a", 2, t1, "")},
+			want: []change{{kind: structures.Modified, path: "a", fields: []string{"size", "mtime"}}},
+		},
+		{
+			name: "ignore times",
+			opts: DiffOptions{IgnoreTimes: true},
+			a:    []Entry{file("a", 1, t0, "")},
+			b:    []Entry{file("a", 1, t1, "")},
+		},
+		{
+			name:   "content change with same size and mtime",
+			hashed: true,
+			a:      []Entry{file("a", 1, t0, "x")},
+			b:      []Entry{file("a", 1, t0, "y")},
+			want:   []change{{kind: structures.Modified, path: "a", fields: []string{"content"}}},
+		},
+		{
+			name: "directory mtime is not a change",
+			a:    []Entry{dir("d", t0)},
+			b:    []Entry{dir("d", t1)},
+		},
+		{
+			name: "type change",
+			a:    []Entry{file("x", 1, t0, "")},
+			b:    []Entry{dir("x", t0)},
+			want: []change{{kind: structures.Modified, path: "x", fields: []string{"type"}}},
+		},
+		{
+			name: "move keeps size and mtime",
+			a:    []Entry{file("src/a.txt", 10, t0, "")},
+			b:    []Entry{file("dst/a.txt", 10, t0, "")},
+			want: []change{{kind: structures.Moved, path: "dst/a.txt", from: "src/a.txt"}},
+		},
+		{
+			name: "different mtime is not a move",
+			a:    []Entry{file("src/a.txt", 10, t0, "")},
+			b:    []Entry{file("dst/a.txt", 10, t1, "")},
+			want: []change{
+				{kind: structures.Added, path: "dst/a.txt"},
+				{kind: structures.Removed, path: "src/a.txt"},
+			},
+		},
+		{
+			name:   "hashes must agree for a move",
+			hashed: true,
+			a:      []Entry{file("a", 10, t0, "x")},
+			b:      []Entry{file("b", 10, t0, "y")},
+			want: []change{
+				{kind: structures.Removed, path: "a"},
+				{kind: structures.Added, path: "b"},
+			},
+		},
+		{
+			name:   "hashed move across copies with ignored times",
+			hashed: true,
+			opts:   DiffOptions{IgnoreTimes: true},
+			a:      []Entry{file("a", 10, t0, "x")},
+			b:      []Entry{file("b", 10, t1, "x")},
+			want:   []change{{kind: structures.Moved, path: "b", from: "a"}},
+		},
+		{
+			name: "no moves without hashes or times",
+			opts: DiffOptions{IgnoreTimes: true},
+			a:    []Entry{file("a", 10, t0, "")},
+			b:    []Entry{file("b", 10, t0, "")},
+			want: []change{
+				{kind: structures.Removed, path: "a"},
+				{kind: structures.Added, path: "b"},
+			},
+		},
+		{
+			name: "same base name is preferred",
+			a:    []Entry{file("x/one", 5, t0, ""), file("x/two", 5, t0, "")},
+			b:    []Entry{file("y/two", 5, t0, ""), file("z/one", 5, t0, "")},
+			want: []change{
+				{kind: structures.Moved, path: "y/two", from: "x/two"},
+				{kind: structures.Moved, path: "z/one", from: "x/one"},
+			},
+		},
+		{
+			name: "each source moves once",
+			a:    []Entry{file("a", 5, t0, "")},
+			b:    []Entry{file("b", 5, t0, ""), file("c", 5, t0, "")},
+			want: []change{
+				{kind: structures.Moved, path: "b", from: "a"},
+				{kind: structures.Added, path: "c"},
+			},
+		},
+		{
+			name: "directories are never moved",
+			a:    []Entry{dir("old", t0)},
+			b:    []Entry{dir("new", t0)},
+			want: []change{
+				{kind: structures.Added, path: "new"},
+				{kind: structures.Removed, path: "old"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Snapshot{Hashed: tt.hashed, Entries: tt.a}
+			b := &Snapshot{Hashed: tt.hashed, Entries: tt.b}
+			if got := summarize(Diff(a, b, tt.opts)); !equal(got, tt.want) {
+				t.Errorf("Diff = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rinimisini112/gls/filter"
+	"github.com/rinimisini112/gls/finder"
+	"github.com/rinimisini112/gls/structures"
+)
+
+const snapshotVersion = 1
+
+type Entry struct {
+	Path    string      `json:"path"`
+	Size    int64       `json:"size"`
+	ModTime time.Time   `json:"mtime"`
+	Mode    os.FileMode `json:"mode"`
+	Target  string      `json:"target,omitempty"`
+	Hash    string      `json:"hash,omitempty"`
+}
+
+type Snapshot struct {
+	Version int       `json:"version"`
+	Name    string    `json:"name"`
+	Root    string    `json:"root"`
+	Taken   time.Time `json:"taken"`
+	Hashed  bool      `json:"hashed"`
+	Options Options   `json:"options"`
+	Entries []Entry   `json:"entries"`
+}
+
+// Options decides which entries are recorded. They are saved with the
+// snapshot so a later rescan of the same tree walks it the same way.
+type Options struct {
+	ShowHidden bool     `json:"hidden,omitempty"`
+	NoIgnore   bool     `json:"noIgnore,omitempty"`
+	Include    []string `json:"include,omitempty"`
+	Exclude    []string `json:"exclude,omitempty"`
+	Hash       bool     `json:"-"`
+}
+
+func (o Options) Equal(other Options) bool {
+	return o.ShowHidden == other.ShowHidden &&
+		o.NoIgnore == other.NoIgnore &&
+		slices.Equal(o.Include, other.Include) &&
+		slices.Equal(o.Exclude, other.Exclude)
+}
+
+func (o Options) String() string {
+	var parts []string
+	if o.ShowHidden {
+		parts = append(parts, "-a")
+	}
+	if o.NoIgnore {
+		parts = append(parts, "--no-ignore")
+	}
+	for _, pattern := range o.Include {
+		parts = append(parts, "--include="+pattern)
+	}
+	for _, pattern := range o.Exclude {
+		parts = append(parts, "--exclude="+pattern)
+	}
+	if len(parts) == 0 {
+		return "default options"
+	}
+	return strings.Join(parts, " ")
+}
+
+func (o Options) finder() (finder.Options, error) {
+	set := &filter.Set{}
+	for _, pattern := range o.Include {
+		if err := set.AddInclude(pattern); err != nil {
+			return finder.Options{}, err
+		}
+	}
+	for _, pattern := range o.Exclude {
+		if err := set.AddExclude(pattern); err != nil {
+			return finder.Options{}, err
+		}
+	}
+	return finder.Options{NoIgnore: o.NoIgnore, NoHidden: !o.ShowHidden, Filter: set}, nil
+}
+
+func Dir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gls", "snapshots"), nil
+}
+
+// Path maps a snapshot name to its file. Names that already look like a path
+// are used as they are, so snapshots can also be kept next to a project.
+func Path(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("snapshot name is empty")
+	}
+	if strings.ContainsRune(name, filepath.Separator) || strings.HasSuffix(name, ".json") {
+		return name, nil
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// Take records every entry below root with paths relative to it. With Hash
+// set, regular files are also hashed with sha256 so content changes are
+// caught even when size and mtime are kept. It fails outright when root is
+// not a readable directory, since an empty snapshot would later diff as the
+// whole tree being added or removed.
+func Take(ctx context.Context, name, root string, opts Options) (*Snapshot, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := readableDir(abs); err != nil {
+		return nil, err
+	}
+	walkOpts, err := opts.finder()
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{Version: snapshotVersion, Name: name, Root: abs, Taken: time.Now(), Hashed: opts.Hash, Options: opts}
+	var errs []error
+
+	err = finder.StreamContext(ctx, "", root, walkOpts, func(file structures.FileInfo) {
+		rel, err := filepath.Rel(root, file.Path)
+		if err != nil {
+			return
+		}
+		snap.Entries = append(snap.Entries, Entry{
+			Path:    filepath.ToSlash(rel),
+			Size:    file.RawSize,
+			ModTime: file.ModTime,
+			Mode:    file.Mode,
+			Target:  file.LinkTarget,
+		})
+	})
+	errs = append(errs, err)
+
+	sort.Slice(snap.Entries, func(i, j int) bool { return snap.Entries[i].Path < snap.Entries[j].Path })
+
+	if opts.Hash {
+		errs = append(errs, hashEntries(ctx, abs, snap.Entries))
+	}
+	return snap, errors.Join(errs...)
+}
+
+func readableDir(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return &finder.DirError{Path: path, Err: errors.New("not a directory")}
+	}
+	if _, err := f.ReadDir(1); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func hashEntries(ctx context.Context, root string, entries []Entry) error {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(entries) {
+		workers = len(entries)
+	}
+
+	failed := make([]error, len(entries))
+	var next atomic.Int64
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(entries) {
+					return
+				}
+				if !entries[i].Mode.IsRegular() {
+					continue
+				}
+
+				path := filepath.Join(root, filepath.FromSlash(entries[i].Path))
+				sum, err := hashFile(path)
+				if err != nil {
+					failed[i] = &finder.DirError{Path: path, Err: err}
+					continue
+				}
+				entries[i].Hash = sum
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(append(failed, ctx.Err())...)
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (s *Snapshot) Save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if snap.Version != snapshotVersion {
+		return nil, fmt.Errorf("%s: unsupported snapshot version %d", path, snap.Version)
+	}
+	return &snap, nil
+}
@@ -0,0 +1,58 @@
+package snapshot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestTakeBadRoot(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, root := range []string{filepath.Join(dir, "missing"), file} {
+		if snap, err := Take(context.Background(), "s", root, Options{}); err == nil || snap != nil {
+			t.Errorf("Take(%q) = %v, %v, want an error and no snapshot", root, snap, err)
+		}
+	}
+}
+
+func TestTakeHidden(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"sub", ".h"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"a", ".c", "sub/b", ".h/d"} {
+		if err := os.WriteFile(filepath.Join(root, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		opts Options
+		want []string
+	}{
+		{Options{NoIgnore: true}, []string{"a", "sub", "sub/b"}},
+		{Options{NoIgnore: true, ShowHidden: true}, []string{".c", ".h", ".h/d", "a", "sub", "sub/b"}},
+	}
+	for _, tt := range tests {
+		snap, err := Take(context.Background(), "s", root, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, entry := range snap.Entries {
+			got = append(got, entry.Path)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Take with %s recorded %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package structures
+
+type DirTree struct {
+	Path      string
+	Depth     int
+	Files     []FileInfo
+	Children  []*DirTree
+	Err       error
+	FileCount int
+	DirCount  int
+	TotalSize int64
+}
+
+func (t *DirTree) Walk(fn func(node *DirTree)) {
+	fn(t)
+	for _, child := range t.Children {
+		child.Walk(fn)
+	}
+}
+
+func (t *DirTree) Flatten() []FileInfo {
+	var files []FileInfo
+	t.Walk(func(node *DirTree) {
+		files = append(files, node.Files...)
+	})
+	return files
+}
@@ -0,0 +1,14 @@
+package structures
+
+type DuplicateSet struct {
+	Size  int64
+	Hash  string
+	Files []FileInfo
+}
+
+func (d DuplicateSet) Wasted() int64 {
+	if len(d.Files) < 2 {
+		return 0
+	}
+	return d.Size * int64(len(d.Files)-1)
+}
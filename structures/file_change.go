@@ -0,0 +1,30 @@
+package structures
+
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota
+	Removed
+	Modified
+	Moved
+)
+
+var changeNames = [...]string{"added", "removed", "modified", "moved"}
+
+func (k ChangeKind) String() string {
+	if int(k) < len(changeNames) {
+		return changeNames[k]
+	}
+	return "unknown"
+}
+
+// FileChange is one difference between two listings of a tree. Paths are
+// relative to the tree's root; From is only set for moves and Fields names
+// what differs for modifications.
+type FileChange struct {
+	Kind   ChangeKind
+	Path   string
+	From   string
+	Size   int64
+	Fields []string
+}
@@ -1,15 +1,78 @@
 package structures
 
+import (
+	"os"
+	"time"
+)
+
 type FileInfo struct {
-	Name         string
-	UserAndGroup string
-	Permissions  string
-	Size         string
-	RawSize      int64
-	ModTime      string
-	IsDir        bool
-	Hidden       bool
-	Path         string
-	Selected     bool
-	Color        string
+	Name           string
+	UserAndGroup   string
+	Permissions    string
+	Mode           os.FileMode
+	Size           string
+	RawSize        int64
+	ModTime        time.Time
+	AccessTime     time.Time
+	ChangeTime     time.Time
+	BirthTime      time.Time
+	IsDir          bool
+	Kind           FileKind
+	IsLink         bool
+	Broken         bool
+	LinkTarget     string
+	Hidden         bool
+	Path           string
+	Inode          uint64
+	Links          uint64
+	Blocks         int64
+	Dev            uint64
+	Rdev           uint64
+	Depth          int
+	Score          int
+	MatchPositions []int
+	Lines          []LineMatch
+	Selected       bool
+	Color          string
+}
+
+func (f FileInfo) IsType(filterType string) bool {
+	switch filterType {
+	case "":
+		return true
+	case "dir":
+		return f.IsDir
+	case "file":
+		return !f.IsDir
+	case "hidden":
+		return f.Hidden
+	case "link":
+		return f.IsLink
+	case "broken":
+		return f.Broken
+	case "device":
+		return f.Kind == KindBlockDevice || f.Kind == KindCharDevice
+	}
+
+	kind, ok := ParseKind(filterType)
+	return ok && f.Kind == kind
+}
+
+func (f FileInfo) Time(field string) time.Time {
+	switch field {
+	case "atime":
+		return f.AccessTime
+	case "ctime":
+		return f.ChangeTime
+	case "birth":
+		return f.BirthTime
+	default:
+		return f.ModTime
+	}
+}
+
+type LineMatch struct {
+	Line  int
+	Text  string
+	Spans [][2]int
 }
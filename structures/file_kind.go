@@ -0,0 +1,78 @@
+package structures
+
+import "os"
+
+type FileKind int
+
+const (
+	KindFile FileKind = iota
+	KindDir
+	KindSymlink
+	KindSocket
+	KindFIFO
+	KindBlockDevice
+	KindCharDevice
+	KindExecutable
+)
+
+var kindNames = map[FileKind]string{
+	KindFile:        "file",
+	KindDir:         "dir",
+	KindSymlink:     "link",
+	KindSocket:      "socket",
+	KindFIFO:        "fifo",
+	KindBlockDevice: "block",
+	KindCharDevice:  "char",
+	KindExecutable:  "exec",
+}
+
+func KindOf(mode os.FileMode) FileKind {
+	switch {
+	case mode.IsDir():
+		return KindDir
+	case mode&os.ModeSymlink != 0:
+		return KindSymlink
+	case mode&os.ModeSocket != 0:
+		return KindSocket
+	case mode&os.ModeNamedPipe != 0:
+		return KindFIFO
+	case mode&os.ModeDevice != 0 && mode&os.ModeCharDevice != 0:
+		return KindCharDevice
+	case mode&os.ModeDevice != 0:
+		return KindBlockDevice
+	case mode.IsRegular() && mode.Perm()&0111 != 0:
+		return KindExecutable
+	default:
+		return KindFile
+	}
+}
+
+func ParseKind(name string) (FileKind, bool) {
+	for kind, kindName := range kindNames {
+		if kindName == name {
+			return kind, true
+		}
+	}
+	return KindFile, false
+}
+
+func (k FileKind) String() string {
+	return kindNames[k]
+}
+
+// Indicator returns the ls -F style suffix for the kind.
+func (k FileKind) Indicator() string {
+	switch k {
+	case KindDir:
+		return "/"
+	case KindSymlink:
+		return "@"
+	case KindSocket:
+		return "="
+	case KindFIFO:
+		return "|"
+	case KindExecutable:
+		return "*"
+	}
+	return ""
+}
@@ -0,0 +1,11 @@
+package sysinfo
+
+// Inode holds the stat fields that identify a file on disk. Blocks counts
+// 512-byte units, as reported by stat(2).
+type Inode struct {
+	Ino    uint64
+	Nlink  uint64
+	Blocks int64
+	Dev    uint64
+	Rdev   uint64
+}
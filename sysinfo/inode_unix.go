@@ -0,0 +1,22 @@
+//go:build linux || darwin
+
+package sysinfo
+
+import (
+	"os"
+	"syscall"
+)
+
+func InodeOf(info os.FileInfo) Inode {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return Inode{}
+	}
+	return Inode{
+		Ino:    uint64(stat.Ino),
+		Nlink:  uint64(stat.Nlink),
+		Blocks: int64(stat.Blocks),
+		Dev:    uint64(stat.Dev),
+		Rdev:   uint64(stat.Rdev),
+	}
+}
@@ -0,0 +1,23 @@
+package sysinfo
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+func Times(info os.FileInfo) (atime, ctime time.Time) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, time.Time{}
+	}
+	return time.Unix(stat.Atimespec.Unix()), time.Unix(stat.Ctimespec.Unix())
+}
+
+func BirthTime(path string, info os.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(stat.Birthtimespec.Unix())
+}
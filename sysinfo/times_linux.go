@@ -0,0 +1,34 @@
+package sysinfo
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func Times(info os.FileInfo) (atime, ctime time.Time) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, time.Time{}
+	}
+	return time.Unix(stat.Atim.Unix()), time.Unix(stat.Ctim.Unix())
+}
+
+// BirthTime reads the creation time with statx. A symlink is only followed
+// when info already describes its target, so the result matches the other
+// fields taken from info.
+func BirthTime(path string, info os.FileInfo) time.Time {
+	flags := unix.AT_SYMLINK_NOFOLLOW
+	if info.Mode()&os.ModeSymlink == 0 {
+		flags = 0
+	}
+
+	var stx unix.Statx_t
+	err := unix.Statx(unix.AT_FDCWD, path, flags, unix.STATX_BTIME, &stx)
+	if err != nil || stx.Mask&unix.STATX_BTIME == 0 {
+		return time.Time{}
+	}
+	return time.Unix(stx.Btime.Sec, int64(stx.Btime.Nsec))
+}
@@ -0,0 +1,16 @@
+//go:build !linux && !darwin
+
+package sysinfo
+
+import (
+	"os"
+	"time"
+)
+
+func Times(info os.FileInfo) (atime, ctime time.Time) {
+	return time.Time{}, time.Time{}
+}
+
+func BirthTime(path string, info os.FileInfo) time.Time {
+	return time.Time{}
+}
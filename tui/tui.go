@@ -10,8 +10,10 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/mholt/archiver/v3"
+	"github.com/rinimisini112/gls/finder"
 	"github.com/rinimisini112/gls/operations"
 	"github.com/rinimisini112/gls/structures"
+	"github.com/rinimisini112/gls/watch"
 	"github.com/rivo/tview"
 )
 
@@ -23,9 +25,12 @@ type UIState struct {
 	CurrentDir string
 	Files      []structures.FileInfo
 	Selected   map[int]struct{}
+	usage      *usageView
+	watcher    *watch.Watcher
+	watchedDir string
 }
 
-func StartInteractiveMode(dir string) {
+func StartInteractiveMode(dir string, showUsage bool, sizeOpts finder.SizeOptions) {
 	app := tview.NewApplication()
 	state := &UIState{
 		App:        app,
@@ -37,12 +42,27 @@ func StartInteractiveMode(dir string) {
 		AddItem(createFileList(state), 0, 1, true).
 		AddItem(createPreviewPane(state), 40, 1, false)
 
+	state.usage = newUsageView(state, sizeOpts)
+
 	state.Pages = tview.NewPages().
-		AddPage("main", flex, true, true)
+		AddPage("main", flex, true, true).
+		AddPage("usage", state.usage.layout, true, false)
+
+	if showUsage {
+		openUsage(state)
+	}
 
 	app.SetRoot(state.Pages, true)
 
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch page, _ := state.Pages.GetFrontPage(); page {
+		case "usage":
+			return state.usage.handleKey(event)
+		case "main":
+		default:
+			return event
+		}
+
 		switch event.Rune() {
 		case 'j', 'J':
 			state.FileList.SetCurrentItem((state.FileList.GetCurrentItem() + 1) % len(state.Files))
@@ -62,6 +82,9 @@ func StartInteractiveMode(dir string) {
 			deleteFile(state)
 		case 's':
 			showStats(state)
+		case 'u':
+			openUsage(state)
+			return nil
 		case ' ':
 			toggleSelection(state)
 		case 'a':
@@ -74,16 +97,27 @@ func StartInteractiveMode(dir string) {
 		return event
 	})
 
+	startWatching(state)
+
 	if err := app.Run(); err != nil {
 		log.Fatal(err)
 	}
+	state.usage.save()
+	if state.watcher != nil {
+		state.watcher.Close()
+	}
+}
+
+func openUsage(state *UIState) {
+	state.Pages.SwitchToPage("usage")
+	state.usage.open(state.CurrentDir)
 }
 
 func createFileList(state *UIState) *tview.List {
 	list := tview.NewList().ShowSecondaryText(false)
 	state.FileList = list
 
-	files, _ := operations.ListFiles(state.CurrentDir, "name")
+	files, _ := operations.ListFiles(state.CurrentDir, "name", false, false, false)
 	state.Files = files
 
 	for _, file := range files {
@@ -112,8 +146,9 @@ func navigateUp(state *UIState) {
 	}
 
 	parentDir := filepath.Dir(state.CurrentDir)
-	files, _ := operations.ListFiles(parentDir, "name")
+	files, _ := operations.ListFiles(parentDir, "name", false, false, false)
 	state.CurrentDir = parentDir
+	watchDir(state, parentDir)
 	state.Files = files
 	state.FileList.Clear()
 
@@ -135,8 +170,9 @@ func enterDirectory(state *UIState) {
 	file := state.Files[currentSelection]
 	if file.IsDir {
 		newDir := file.Path
-		files, _ := operations.ListFiles(newDir, "name")
+		files, _ := operations.ListFiles(newDir, "name", false, false, false)
 		state.CurrentDir = newDir
+		watchDir(state, newDir)
 		state.Files = files
 		state.FileList.Clear()
 		for _, file := range files {
@@ -0,0 +1,283 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rinimisini112/gls/finder"
+	"github.com/rinimisini112/gls/operations"
+	"github.com/rinimisini112/gls/structures"
+	"github.com/rivo/tview"
+)
+
+const usageBarWidth = 20
+
+type usageItem struct {
+	file  structures.FileInfo
+	usage finder.DirUsage
+	done  bool
+}
+
+type usageView struct {
+	state     *UIState
+	scanner   *finder.SizeScanner
+	diskUsage bool
+	layout    *tview.Flex
+	table     *tview.Table
+	status    *tview.TextView
+	dir       string
+	items     []*usageItem
+	scan      chan struct{}
+}
+
+func newUsageView(state *UIState, opts finder.SizeOptions) *usageView {
+	v := &usageView{
+		state:     state,
+		scanner:   finder.NewSizeScanner(opts),
+		diskUsage: opts.DiskUsage,
+		table:     tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		status:    tview.NewTextView().SetDynamicColors(true),
+	}
+
+	if path, err := finder.SizeCachePath(); err == nil {
+		v.scanner.Load(path)
+	}
+
+	v.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.status, 1, 0, false)
+	return v
+}
+
+func (v *usageView) save() {
+	if path, err := finder.SizeCachePath(); err == nil {
+		v.scanner.Save(path)
+	}
+}
+
+func (v *usageView) open(dir string) {
+	files, err := operations.ListFiles(dir, "name", false, false, false)
+	if err != nil {
+		v.status.SetText(fmt.Sprintf("[red]❌ %v", err))
+		return
+	}
+
+	v.stop()
+	v.scanner.Refresh()
+	v.dir = dir
+	v.scan = make(chan struct{})
+	v.items = v.items[:0]
+
+	var pending []*usageItem
+	for _, file := range files {
+		item := &usageItem{file: file, usage: finder.DirUsage{Size: v.fileSize(file)}, done: true}
+		if file.IsDir && !file.IsLink {
+			item.usage.Size = 0
+			item.done = false
+			pending = append(pending, item)
+		}
+		v.items = append(v.items, item)
+	}
+
+	v.table.Select(1, 0)
+	v.render()
+	go v.scanItems(v.scan, pending)
+}
+
+func (v *usageView) stop() {
+	if v.scan != nil {
+		close(v.scan)
+		v.scan = nil
+	}
+}
+
+func (v *usageView) fileSize(file structures.FileInfo) int64 {
+	if v.diskUsage {
+		return file.Blocks * 512
+	}
+	return file.RawSize
+}
+
+func (v *usageView) scanItems(scan chan struct{}, pending []*usageItem) {
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(200 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-scan:
+				return
+			case <-ticker.C:
+				v.state.App.QueueUpdateDraw(func() {
+					if v.scan == scan {
+						v.renderStatus()
+					}
+				})
+			}
+		}
+	}()
+
+	for _, item := range pending {
+		select {
+		case <-scan:
+			return
+		default:
+		}
+
+		usage, _ := v.scanner.Usage(item.file.Path)
+		v.state.App.QueueUpdateDraw(func() {
+			if v.scan != scan {
+				return
+			}
+			item.usage = usage
+			item.done = true
+			v.render()
+		})
+	}
+}
+
+func (v *usageView) total() int64 {
+	var total int64
+	for _, item := range v.items {
+		total += item.usage.Size
+	}
+	return total
+}
+
+func (v *usageView) render() {
+	var selected string
+	if row, _ := v.table.GetSelection(); row > 0 && row <= len(v.items) {
+		selected = v.items[row-1].file.Path
+	}
+
+	sort.SliceStable(v.items, func(i, j int) bool {
+		return v.items[i].usage.Size > v.items[j].usage.Size
+	})
+
+	v.table.Clear()
+	for col, header := range []string{"Size", "%", "", "Items", "Name"} {
+		v.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+
+	total := v.total()
+	for i, item := range v.items {
+		row := i + 1
+
+		size := humanize.Bytes(uint64(item.usage.Size))
+		if !item.done {
+			size = "…"
+		}
+
+		var fraction float64
+		if total > 0 {
+			fraction = float64(item.usage.Size) / float64(total)
+		}
+		filled := int(fraction*usageBarWidth + 0.5)
+		bar := "[" + strings.Repeat("█", filled) + strings.Repeat("░", usageBarWidth-filled) + "]"
+
+		items := ""
+		name := "📄 " + item.file.Name
+		if item.file.IsDir {
+			name = "📂 " + item.file.Name + "/"
+			items = fmt.Sprintf("%d", item.usage.Items)
+		}
+
+		v.table.SetCell(row, 0, tview.NewTableCell(size).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 1, tview.NewTableCell(fmt.Sprintf("%5.1f%%", fraction*100)).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 2, tview.NewTableCell(bar))
+		v.table.SetCell(row, 3, tview.NewTableCell(items).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 4, tview.NewTableCell(name).SetExpansion(1))
+
+		if item.file.Path == selected {
+			v.table.Select(row, 0)
+		}
+	}
+
+	v.renderStatus()
+}
+
+func (v *usageView) renderStatus() {
+	pending := 0
+	for _, item := range v.items {
+		if !item.done {
+			pending++
+		}
+	}
+
+	text := fmt.Sprintf("📂 %s  Total: %s", v.dir, humanize.Bytes(uint64(v.total())))
+	if pending > 0 {
+		text += fmt.Sprintf("  [yellow]⏳ Scanning… %d/%d directories left, %d read", pending, len(v.items), v.scanner.Scanned())
+	}
+	v.status.SetText(text + "  [gray]l: open  h: up  d: delete  q: back")
+}
+
+func (v *usageView) current() *usageItem {
+	row, _ := v.table.GetSelection()
+	if row < 1 || row > len(v.items) {
+		return nil
+	}
+	return v.items[row-1]
+}
+
+func (v *usageView) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyEnter || event.Rune() == 'l':
+		if item := v.current(); item != nil && item.file.IsDir {
+			v.open(item.file.Path)
+		}
+		return nil
+	case event.Rune() == 'h':
+		if parent := filepath.Dir(v.dir); parent != v.dir {
+			v.open(parent)
+		}
+		return nil
+	case event.Rune() == 'd':
+		if item := v.current(); item != nil {
+			v.confirmDelete(item)
+		}
+		return nil
+	case event.Key() == tcell.KeyEscape || event.Rune() == 'q':
+		v.stop()
+		v.state.Pages.SwitchToPage("main")
+		return nil
+	}
+	return event
+}
+
+func (v *usageView) confirmDelete(item *usageItem) {
+	msg := fmt.Sprintf("Delete %s (%s)?", item.file.Path, humanize.Bytes(uint64(item.usage.Size)))
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			v.state.Pages.RemovePage("confirm")
+			if label != "Delete" {
+				return
+			}
+
+			remove := os.Remove
+			if item.file.IsDir {
+				remove = os.RemoveAll
+			}
+			if err := remove(item.file.Path); err != nil {
+				v.status.SetText(fmt.Sprintf("[red]❌ %v", err))
+				return
+			}
+
+			v.open(v.dir)
+		})
+
+	v.state.Pages.AddPage("confirm", modal, false, true)
+}
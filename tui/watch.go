@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rinimisini112/gls/operations"
+	"github.com/rinimisini112/gls/watch"
+)
+
+const refreshDelay = 150 * time.Millisecond
+
+// startWatching refreshes the file list whenever the current directory
+// changes on disk. Without inotify the list simply stays as loaded.
+func startWatching(state *UIState) {
+	w, err := watch.New()
+	if err != nil {
+		return
+	}
+	state.watcher = w
+	state.watchedDir = state.CurrentDir
+	w.Add(state.CurrentDir)
+
+	go func() {
+		var pending <-chan time.Time
+		for {
+			select {
+			case _, ok := <-w.Events():
+				if !ok {
+					return
+				}
+				if pending == nil {
+					pending = time.After(refreshDelay)
+				}
+			case <-pending:
+				pending = nil
+				state.App.QueueUpdateDraw(func() {
+					reloadFiles(state)
+				})
+			}
+		}
+	}()
+}
+
+func watchDir(state *UIState, dir string) {
+	if state.watcher == nil || dir == state.watchedDir {
+		return
+	}
+	state.watcher.Remove(state.watchedDir)
+	state.watcher.Add(dir)
+	state.watchedDir = dir
+}
+
+// reloadFiles re-lists the current directory, keeping the cursor and the
+// selection on the same files where they still exist.
+func reloadFiles(state *UIState) {
+	files, err := operations.ListFiles(state.CurrentDir, "name", false, false, false)
+	if err != nil {
+		return
+	}
+
+	current := ""
+	if i := state.FileList.GetCurrentItem(); i < len(state.Files) {
+		current = state.Files[i].Path
+	}
+	selected := make(map[string]bool, len(state.Selected))
+	for i := range state.Selected {
+		if i < len(state.Files) {
+			selected[state.Files[i].Path] = true
+		}
+	}
+
+	state.Files = files
+	state.Selected = make(map[int]struct{})
+	state.FileList.Clear()
+
+	for i, file := range files {
+		icon := "📄"
+		if file.IsDir {
+			icon = "📂"
+		}
+		state.FileList.AddItem(fmt.Sprintf("%s %s", icon, file.Name), "", 0, nil)
+
+		if file.Path == current {
+			state.FileList.SetCurrentItem(i)
+		}
+		if selected[file.Path] {
+			state.Selected[i] = struct{}{}
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rinimisini112/gls/structures"
+	"github.com/rinimisini112/gls/watch"
+)
+
+const (
+	watchHighlight = 3 * time.Second
+	watchPoll      = time.Second
+	watchDebounce  = 200 * time.Millisecond
+)
+
+type change int
+
+const (
+	changeAdded change = iota + 1
+	changeModified
+	changeRemoved
+)
+
+func (c change) paint(text string) string {
+	switch c {
+	case changeAdded:
+		return "\033[1;32m" + text + "\033[0m"
+	case changeModified:
+		return "\033[1;33m" + text + "\033[0m"
+	default:
+		return "\033[9;31m" + text + "\033[0m"
+	}
+}
+
+type watchMark struct {
+	change change
+	file   structures.FileInfo
+	at     time.Time
+}
+
+// watchView is one listed directory in watch mode. It remembers the previous
+// listing so each refresh can mark what appeared, changed or disappeared.
+type watchView struct {
+	dir   string
+	files []structures.FileInfo
+	seen  map[string]structures.FileInfo
+	marks map[string]watchMark
+	err   error
+}
+
+func (v *watchView) update(files []structures.FileInfo, err error, now time.Time) bool {
+	changed := (err == nil) != (v.err == nil)
+	v.err = err
+	if err != nil {
+		return changed
+	}
+
+	current := make(map[string]structures.FileInfo, len(files))
+	for _, file := range files {
+		current[file.Path] = file
+		if v.seen == nil {
+			continue
+		}
+
+		old, ok := v.seen[file.Path]
+		switch {
+		case !ok:
+			v.marks[file.Path] = watchMark{changeAdded, file, now}
+			changed = true
+		case !old.ModTime.Equal(file.ModTime) || old.RawSize != file.RawSize || old.Mode != file.Mode:
+			v.marks[file.Path] = watchMark{changeModified, file, now}
+			changed = true
+		}
+	}
+
+	for path, old := range v.seen {
+		if _, ok := current[path]; !ok {
+			v.marks[path] = watchMark{changeRemoved, old, now}
+			changed = true
+		}
+	}
+
+	v.files = files
+	v.seen = current
+	return changed
+}
+
+// expire drops marks older than watchHighlight and reports when the next one
+// is due to fade.
+func (v *watchView) expire(now time.Time) time.Time {
+	var next time.Time
+	for path, mark := range v.marks {
+		due := mark.at.Add(watchHighlight)
+		if !due.After(now) {
+			delete(v.marks, path)
+			continue
+		}
+		if next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+	return next
+}
+
+func (v *watchView) render(buf *bytes.Buffer, display displayOptions) {
+	fmt.Fprintf(buf, "\n📂 Listing: %s\n", v.dir)
+	if v.err != nil {
+		fmt.Fprintln(buf, "❌ Error:", v.err)
+		return
+	}
+
+	files := slices.Clip(v.files)
+	display.changes = make(map[string]change, len(v.marks))
+	for path, mark := range v.marks {
+		display.changes[path] = mark.change
+		if mark.change == changeRemoved {
+			files = append(files, mark.file)
+		}
+	}
+	writeTable(buf, files, display)
+}
+
+// runWatch keeps the table listing of dirs on screen, redrawing it in place
+// whenever one of them changes. It relies on inotify where available and
+// otherwise re-lists the directories every second. Full directory sizes can
+// change anywhere below a listed directory, so they are always polled.
+func runWatch(dirs []string, display displayOptions, list func(string) ([]structures.FileInfo, error)) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	views := make([]*watchView, len(dirs))
+	for i, dir := range dirs {
+		views[i] = &watchView{dir: dir, marks: make(map[string]watchMark)}
+	}
+
+	var events <-chan watch.Event
+	var errs <-chan error
+	mode := "polling"
+	if w, err := watch.New(); err == nil {
+		defer w.Close()
+		for _, dir := range dirs {
+			if err = w.Add(dir); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			events, errs = w.Events(), w.Errors()
+			mode = "inotify"
+		}
+	}
+
+	var poll <-chan time.Time
+	if events == nil || display.sizes != nil {
+		ticker := time.NewTicker(watchPoll)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	fmt.Print("\033[?25l\033[H\033[2J")
+	defer fmt.Print("\033[?25h")
+
+	var expire <-chan time.Time
+	draw := func(now time.Time) {
+		var next time.Time
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "👀 Watching with %s, updated %s, press Ctrl-C to stop\n", mode, now.Format("15:04:05"))
+		for _, v := range views {
+			due := v.expire(now)
+			if !due.IsZero() && (next.IsZero() || due.Before(next)) {
+				next = due
+			}
+			v.render(&buf, display)
+		}
+
+		frame := strings.ReplaceAll(buf.String(), "\n", "\033[K\n")
+		fmt.Print("\033[H" + frame + "\033[J")
+
+		expire = nil
+		if !next.IsZero() {
+			expire = time.After(next.Sub(now))
+		}
+	}
+
+	refresh := func() bool {
+		now := time.Now()
+		changed := false
+		if display.sizes != nil {
+			display.sizes.Refresh()
+		}
+		for _, v := range views {
+			files, err := list(v.dir)
+			if v.update(files, err, now) {
+				changed = true
+			}
+		}
+		return changed
+	}
+
+	refresh()
+	draw(time.Now())
+
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return
+
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if pending == nil {
+				pending = time.After(watchDebounce)
+			}
+
+		case <-errs:
+			if pending == nil {
+				pending = time.After(watchDebounce)
+			}
+
+		case <-pending:
+			pending = nil
+			if refresh() {
+				draw(time.Now())
+			}
+
+		case <-poll:
+			if refresh() {
+				draw(time.Now())
+			}
+
+		case <-expire:
+			draw(time.Now())
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package watch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const watchMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_ATTRIB |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_DELETE_SELF | unix.IN_MOVE_SELF
+
+// Watcher reports changes to the entries of the directories added to it. It
+// does not descend on its own; callers add subdirectories they care about.
+type Watcher struct {
+	fd     int
+	file   *os.File
+	mu     sync.Mutex
+	paths  map[int]string
+	wds    map[string]int
+	events chan Event
+	errors chan error
+	done   chan struct{}
+	once   sync.Once
+}
+
+func New() (*Watcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		fd:     fd,
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		paths:  make(map[int]string),
+		wds:    make(map[string]int),
+		events: make(chan Event, 256),
+		errors: make(chan error, 8),
+		done:   make(chan struct{}),
+	}
+	go w.read()
+	return w, nil
+}
+
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+func (w *Watcher) Add(dir string) error {
+	wd, err := unix.InotifyAddWatch(w.fd, dir, watchMask)
+	if err != nil {
+		return &os.PathError{Op: "watch", Path: dir, Err: err}
+	}
+
+	w.mu.Lock()
+	w.paths[wd] = dir
+	w.wds[dir] = wd
+	w.mu.Unlock()
+	return nil
+}
+
+func (w *Watcher) Remove(dir string) error {
+	w.mu.Lock()
+	wd, ok := w.wds[dir]
+	delete(w.wds, dir)
+	delete(w.paths, wd)
+	w.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	_, err := unix.InotifyRmWatch(w.fd, uint32(wd))
+	return err
+}
+
+func (w *Watcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.file.Close()
+	})
+	return err
+}
+
+func (w *Watcher) read() {
+	defer close(w.events)
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				w.fail(err)
+			}
+			return
+		}
+
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			raw := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + unix.SizeofInotifyEvent
+			name := strings.TrimRight(string(buf[nameStart:nameStart+int(raw.Len)]), "\x00")
+			offset = nameStart + int(raw.Len)
+
+			if raw.Mask&unix.IN_Q_OVERFLOW != 0 {
+				w.fail(ErrOverflow)
+				continue
+			}
+
+			w.mu.Lock()
+			dir, ok := w.paths[int(raw.Wd)]
+			if raw.Mask&unix.IN_IGNORED != 0 {
+				delete(w.paths, int(raw.Wd))
+				delete(w.wds, dir)
+			}
+			w.mu.Unlock()
+			if !ok || raw.Mask&unix.IN_IGNORED != 0 {
+				continue
+			}
+
+			event := Event{Path: filepath.Join(dir, name), Op: opOf(raw.Mask), IsDir: raw.Mask&unix.IN_ISDIR != 0}
+			if event.Op == 0 {
+				continue
+			}
+
+			select {
+			case w.events <- event:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+func (w *Watcher) fail(err error) {
+	select {
+	case w.errors <- err:
+	default:
+	}
+}
+
+func opOf(mask uint32) Op {
+	var op Op
+	if mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+		op |= Create
+	}
+	if mask&(unix.IN_DELETE|unix.IN_DELETE_SELF) != 0 {
+		op |= Remove
+	}
+	if mask&(unix.IN_MOVED_FROM|unix.IN_MOVE_SELF) != 0 {
+		op |= Rename
+	}
+	if mask&unix.IN_MODIFY != 0 {
+		op |= Write
+	}
+	if mask&unix.IN_ATTRIB != 0 {
+		op |= Chmod
+	}
+	return op
+}
@@ -0,0 +1,43 @@
+package watch
+
+import (
+	"errors"
+	"strings"
+)
+
+type Op uint32
+
+const (
+	Create Op = 1 << iota
+	Remove
+	Rename
+	Write
+	Chmod
+)
+
+var ErrOverflow = errors.New("too many filesystem events, some were dropped")
+
+type Event struct {
+	Path  string
+	Op    Op
+	IsDir bool
+}
+
+func (op Op) String() string {
+	var names []string
+	for _, name := range []struct {
+		op   Op
+		name string
+	}{
+		{Create, "create"},
+		{Remove, "remove"},
+		{Rename, "rename"},
+		{Write, "write"},
+		{Chmod, "chmod"},
+	} {
+		if op&name.op != 0 {
+			names = append(names, name.name)
+		}
+	}
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+package watch
+
+import "errors"
+
+type Watcher struct{}
+
+func New() (*Watcher, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (w *Watcher) Events() <-chan Event    { return nil }
+func (w *Watcher) Errors() <-chan error    { return nil }
+func (w *Watcher) Add(dir string) error    { return errors.ErrUnsupported }
+func (w *Watcher) Remove(dir string) error { return nil }
+func (w *Watcher) Close() error            { return nil }